@@ -0,0 +1,142 @@
+package go_fuzz_utils
+
+import "reflect"
+
+// GetParamsAliasBias obtains the probability of pointers, slices and maps reusing a value of the same type created
+// earlier in the same Fill call (represented as a float between 0 and 1).
+func (t *TypeProvider) GetParamsAliasBias() float32 {
+	return t.aliasBias
+}
+
+// SetParamsAliasBias sets the probability of pointers, slices and maps reusing a value of the same type created
+// earlier in the same Fill call, rather than a newly created one (represented as a float between 0 and 1). Values are
+// available for reuse as soon as they're created, before their contents are filled, so Fill can produce shared nodes
+// and cycles, such as DAGs and cyclic graphs. Values beyond the depth limit may also reuse values, so the leaves of a
+// graph can link back to its nodes. Slices and maps are only reused if their length is within the bounds for the
+// value, and pointers with field constraints on the value they point to are never reused. The bias defaults to zero,
+// in which case no decisions are made.
+// Returns an error if the bias is not within the [0,1] range.
+func (t *TypeProvider) SetParamsAliasBias(bias float32) error {
+	// Validate our parameters and set them accordingly
+	if bias < 0 || bias > 1 {
+		return &InvalidParamError{Param: "bias", Reason: "bias must be between [0,1]"}
+	}
+	t.aliasBias = bias
+	return nil
+}
+
+// withAliases calls the provided function with a new set of values which may be reused, which only lasts for the
+// duration of the call, as done for each call to Fill.
+// Returns the error returned by the provided function.
+func (t *TypeProvider) withAliases(fn func() error) error {
+	// Start a new set of values, restoring the previous set once we're done.
+	aliases := t.aliases
+	t.aliases = make(map[reflect.Type][]reflect.Value)
+	defer func() {
+		t.aliases = aliases
+	}()
+	return fn()
+}
+
+// addAlias adds a copy of the provided pointer, slice or map to the values which may be reused during the current Fill
+// call. Nothing is added if the alias bias is zero or no Fill call is in progress.
+func (t *TypeProvider) addAlias(v reflect.Value) {
+	if t.aliasBias <= 0 || t.aliases == nil {
+		return
+	}
+	alias := reflect.New(v.Type()).Elem()
+	alias.Set(v)
+	t.aliases[v.Type()] = append(t.aliases[v.Type()], alias)
+}
+
+// aliasCandidates obtains the values created during the current Fill call which may be reused for the provided
+// pointer, slice or map, given its field constraints.
+// Returns the values which may be reused, or nil if the alias bias is zero.
+func (t *TypeProvider) aliasCandidates(v reflect.Value, constraints *fieldConstraints) []reflect.Value {
+	// If we're not aliasing values, there are no candidates.
+	if t.aliasBias <= 0 {
+		return nil
+	}
+
+	// Determine the length bounds candidates must satisfy. Pointers whose values are constrained are never reused, as
+	// the values they point to may not satisfy their constraints.
+	minLength, maxLength := 0, 0
+	if v.Kind() == reflect.Slice {
+		minLength, maxLength = constraints.getLengthBounds(t.sliceMinSize, t.sliceMaxSize)
+	} else if v.Kind() == reflect.Map {
+		minLength, maxLength = constraints.getLengthBounds(t.mapMinSize, t.mapMaxSize)
+	} else if constraints.elemConstraints().hasValueConstraints() {
+		return nil
+	}
+
+	// Collect the values of our type which satisfy our bounds.
+	var candidates []reflect.Value
+	for _, alias := range t.aliases[v.Type()] {
+		if v.Kind() == reflect.Ptr || (alias.Len() >= minLength && alias.Len() <= maxLength) {
+			candidates = append(candidates, alias)
+		}
+	}
+	return candidates
+}
+
+// fillAlias determines whether a pointer, slice or map should reuse a value of the same type created earlier in the
+// current Fill call, given the alias bias, and sets it if so. No decisions are made if the bias is zero or there are
+// no values which may be reused.
+// Returns a boolean indicating whether a value was reused.
+func (t *TypeProvider) fillAlias(v reflect.Value, constraints *fieldConstraints) bool {
+	// Determine if we should reuse a value.
+	candidates := t.aliasCandidates(v, constraints)
+	if len(candidates) == 0 || !t.getRandomBool(t.aliasBias) {
+		return false
+	}
+
+	// Select one of our values and set it.
+	v.Set(candidates[t.getRandomSize(0, len(candidates)-1)])
+	return true
+}
+
+// sameReference determines whether two pointers, slices or maps of the same type refer to the same value. Slices only
+// refer to the same value if they also have the same length and capacity.
+func sameReference(a reflect.Value, b reflect.Value) bool {
+	if a.Pointer() != b.Pointer() {
+		return false
+	}
+	return a.Kind() != reflect.Slice || (a.Len() == b.Len() && a.Cap() == b.Cap())
+}
+
+// reference describes the value a pointer, slice or map refers to, as compared by sameReference.
+type reference struct {
+	// typ represents the type of the pointer, slice or map.
+	typ reflect.Type
+	// ptr represents the address the value refers to.
+	ptr uintptr
+	// length represents the length of the value if it is a slice.
+	length int
+	// capacity represents the capacity of the value if it is a slice.
+	capacity int
+}
+
+// referenceSet tracks the pointers, slices and maps walked so far, so that values which are shared or which refer to
+// themselves can be detected.
+type referenceSet map[reference]bool
+
+// visit marks a non-nil pointer, slice or map as walked. Values referring to zero-sized memory may share an address
+// without sharing a value, so they are never considered walked.
+// Returns a boolean indicating whether the value was already walked.
+func (s referenceSet) visit(v reflect.Value) bool {
+	// Determine if our value refers to any memory.
+	if v.Kind() != reflect.Map && (v.Type().Elem().Size() == 0 || (v.Kind() == reflect.Slice && v.Cap() == 0)) {
+		return false
+	}
+
+	// Mark our value as walked.
+	key := reference{typ: v.Type(), ptr: v.Pointer()}
+	if v.Kind() == reflect.Slice {
+		key.length, key.capacity = v.Len(), v.Cap()
+	}
+	if s[key] {
+		return true
+	}
+	s[key] = true
+	return false
+}
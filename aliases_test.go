@@ -0,0 +1,193 @@
+package go_fuzz_utils_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+// graphNode describes a node in a graph which may contain shared nodes and cycles.
+type graphNode struct {
+	ID    uint8
+	Edges []*graphNode
+}
+
+// sharedValues describes a struct with pointers, slices and maps which may be reused.
+type sharedValues struct {
+	P0 *uint8
+	P1 *uint8 `fuzz:"min=1,max=5"`
+	P2 *uint8
+	S1 []int16
+	S2 []int16
+	S3 []int16 `fuzz:"min=20,max=20"`
+	M1 map[uint8]bool
+	M2 map[uint8]bool
+}
+
+// hasSharedNodes determines whether any node in a graph can be reached through more than one edge, including through
+// a cycle back to the root.
+func hasSharedNodes(root *graphNode) bool {
+	seen := map[*graphNode]bool{}
+	var visit func(n *graphNode) bool
+	visit = func(n *graphNode) bool {
+		if n == nil {
+			return false
+		} else if seen[n] {
+			return true
+		}
+		seen[n] = true
+		for _, edge := range n.Edges {
+			if visit(edge) {
+				return true
+			}
+		}
+		return false
+	}
+	return visit(root)
+}
+
+func TestFillAliases(t *testing.T) {
+	// Create our type provider, always reusing values where possible.
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, tp.GetParamsAliasBias())
+	assert.Nil(t, tp.SetParamsBiasesCommon(0, 0))
+	assert.Nil(t, tp.SetParamsAliasBias(1))
+
+	// Values should reuse the first value of their type, unless they're constrained.
+	var v sharedValues
+	assert.Nil(t, tp.Fill(&v))
+	assert.True(t, v.P0 != v.P1)
+	assert.True(t, v.P2 == v.P0 || v.P2 == v.P1)
+	assert.GreaterOrEqual(t, *v.P1, uint8(1))
+	assert.LessOrEqual(t, *v.P1, uint8(5))
+	assert.Len(t, v.S2, len(v.S1))
+	if len(v.S1) > 0 {
+		assert.True(t, &v.S1[0] == &v.S2[0])
+	}
+	assert.Len(t, v.S3, 20)
+	v.M1[0xAA] = true
+	assert.True(t, v.M2[0xAA])
+
+	// Values are only reused within a single Fill call.
+	var v2 sharedValues
+	assert.Nil(t, tp.Fill(&v2))
+	assert.True(t, v.P0 != v2.P0)
+
+	// Invalid biases return an error.
+	assert.True(t, errors.Is(tp.SetParamsAliasBias(2), go_fuzz_utils.ErrInvalidParam))
+}
+
+func TestFillGraphs(t *testing.T) {
+	// Fill graphs from random data, with and without reusing values.
+	sharedCount := 0
+	for i := int64(0); i < 50; i++ {
+		data := make([]byte, 0x1000)
+		rand.New(rand.NewSource(i)).Read(data)
+		tp, err := go_fuzz_utils.NewTypeProvider(data)
+		assert.Nil(t, err)
+		assert.Nil(t, tp.SetParamsSliceBounds(0, 3))
+		assert.Nil(t, tp.SetParamsDepthLimit(4))
+		tp.SetParamsDepthCountContainers(true)
+
+		// Without reusing values, graphs are always trees.
+		var tree *graphNode
+		assert.Nil(t, tp.Fill(&tree))
+		assert.False(t, hasSharedNodes(tree))
+
+		// With reusing values, we should see shared nodes and cycles.
+		assert.Nil(t, tp.Reset())
+		assert.Nil(t, tp.SetParamsAliasBias(0.5))
+		var graph *graphNode
+		if tp.Fill(&graph) == nil && hasSharedNodes(graph) {
+			sharedCount++
+		}
+	}
+	assert.Greater(t, sharedCount, 0)
+}
+
+func TestEncodeAliases(t *testing.T) {
+	// Create a cyclic graph where the root node is shared.
+	a := &graphNode{ID: 1}
+	b := &graphNode{ID: 2, Edges: []*graphNode{a}}
+	a.Edges = []*graphNode{b, a}
+
+	// Encode our graph and fill a new graph from the encoded data.
+	tp, err := go_fuzz_utils.NewTypeProvider(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsAliasBias(0.5))
+	data, err := tp.Encode(&a)
+	assert.Nil(t, err)
+	tp, err = go_fuzz_utils.NewTypeProvider(data)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsAliasBias(0.5))
+	var filled *graphNode
+	assert.Nil(t, tp.Fill(&filled))
+
+	// Our graph should have the same structure.
+	assert.EqualValues(t, 1, filled.ID)
+	assert.Len(t, filled.Edges, 2)
+	assert.EqualValues(t, 2, filled.Edges[0].ID)
+	assert.True(t, filled.Edges[0].Edges[0] == filled)
+	assert.True(t, filled.Edges[1] == filled)
+
+	// Shared values cannot be encoded if they must be reused, but aren't.
+	assert.Nil(t, tp.SetParamsAliasBias(1))
+	_, err = tp.Encode(&sharedValues{P0: new(uint8), P1: new(uint8), P2: new(uint8)})
+	assert.NotNil(t, err)
+}
+
+func TestEncodeCycles(t *testing.T) {
+	// Create a node which refers to itself.
+	n := &linkedNode{Value: 7}
+	n.Next = n
+
+	// Without reusing values, cyclic values cannot be encoded.
+	tp, err := go_fuzz_utils.NewTypeProvider(nil)
+	assert.Nil(t, err)
+	_, err = tp.Encode(&n)
+	assert.NotNil(t, err)
+
+	// Neither can values which are shared.
+	p := new(uint8)
+	_, err = tp.Encode(&sharedValues{P0: p, P1: p, P2: new(uint8)})
+	assert.NotNil(t, err)
+
+	// With reusing values, our node should be reproduced.
+	assert.Nil(t, tp.SetParamsAliasBias(0.5))
+	data, err := tp.Encode(&n)
+	assert.Nil(t, err)
+	tp, err = go_fuzz_utils.NewTypeProvider(data)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsAliasBias(0.5))
+	var filled *linkedNode
+	assert.Nil(t, tp.Fill(&filled))
+	assert.EqualValues(t, 7, filled.Value)
+	assert.True(t, filled.Next == filled)
+}
+
+func TestGetAliases(t *testing.T) {
+	// Create our type provider, always reusing values where possible.
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsBiasesCommon(0, 0))
+	assert.Nil(t, tp.SetParamsAliasBias(1))
+
+	// Values obtained through the generic helpers should reuse values in the same way as Fill.
+	v, err := go_fuzz_utils.Get[sharedValues](tp)
+	assert.Nil(t, err)
+	assert.True(t, v.P2 == v.P0 || v.P2 == v.P1)
+	v.M1[0xAA] = true
+	assert.True(t, v.M2[0xAA])
+
+	// Pointers within a slice should reuse the first of them.
+	ptrs, err := go_fuzz_utils.GetSlice[*uint8](tp)
+	assert.Nil(t, err)
+	assert.Greater(t, len(ptrs), 1)
+	for _, ptr := range ptrs {
+		assert.True(t, ptr == ptrs[0])
+	}
+}
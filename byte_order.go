@@ -0,0 +1,199 @@
+package go_fuzz_utils
+
+import "encoding/binary"
+
+// uintFromBytes constructs an unsigned integer from up to 8 bytes, using the TypeProvider's byte order.
+// Returns the constructed integer.
+func (t *TypeProvider) uintFromBytes(b []byte) uint64 {
+	x := uint64(0)
+	for i := range b {
+		// Big-endian values start with their most significant byte, little-endian values with their least.
+		if t.byteOrder == binary.LittleEndian {
+			x |= uint64(b[i]) << (8 * i)
+		} else {
+			x = (x << 8) | uint64(b[i])
+		}
+	}
+	return x
+}
+
+// uintToBytes obtains the bytes representing the provided unsigned integer in the provided number of bytes, using the
+// TypeProvider's byte order. This is the inverse of uintFromBytes.
+// Returns the bytes representing the integer.
+func (t *TypeProvider) uintToBytes(x uint64, size int) []byte {
+	b := make([]byte, 8)
+	if t.byteOrder == binary.LittleEndian {
+		binary.LittleEndian.PutUint64(b, x)
+		return b[:size]
+	}
+	binary.BigEndian.PutUint64(b, x)
+	return b[8-size:]
+}
+
+// readUint16 obtains an uint16 from the current position in the buffer, using the TypeProvider's byte order.
+// This advances the position by 2.
+// Returns the read uint16, or an error if the end of stream has been reached.
+func (t *TypeProvider) readUint16() (uint16, error) {
+	// Obtain the data to back our value
+	b, err := t.getFixedBytes(2)
+	if err != nil {
+		return 0, err
+	}
+	return t.byteOrder.Uint16(b), nil
+}
+
+// readUint32 obtains an uint32 from the current position in the buffer, using the TypeProvider's byte order.
+// This advances the position by 4.
+// Returns the read uint32, or an error if the end of stream has been reached.
+func (t *TypeProvider) readUint32() (uint32, error) {
+	// Obtain the data to back our value
+	b, err := t.getFixedBytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return t.byteOrder.Uint32(b), nil
+}
+
+// readUint64 obtains an uint64 from the current position in the buffer, using the TypeProvider's byte order.
+// This advances the position by 8.
+// Returns the read uint64, or an error if the end of stream has been reached.
+func (t *TypeProvider) readUint64() (uint64, error) {
+	// Obtain the data to back our value
+	b, err := t.getFixedBytes(8)
+	if err != nil {
+		return 0, err
+	}
+	return t.byteOrder.Uint64(b), nil
+}
+
+// GetUint16LE obtains a little-endian uint16 from the current position in the buffer, regardless of the byte order
+// parameter.
+// This advances the position by 2.
+// Returns the read uint16, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetUint16LE() (uint16, error) {
+	// Obtain the data to back our value
+	b, err := t.getFixedBytes(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(b), nil
+}
+
+// GetUint16BE obtains a big-endian uint16 from the current position in the buffer, regardless of the byte order
+// parameter.
+// This advances the position by 2.
+// Returns the read uint16, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetUint16BE() (uint16, error) {
+	// Obtain the data to back our value
+	b, err := t.getFixedBytes(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(b), nil
+}
+
+// GetInt16LE obtains a little-endian int16 from the current position in the buffer, regardless of the byte order
+// parameter.
+// This advances the position by 2.
+// Returns the read int16, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetInt16LE() (int16, error) {
+	x, err := t.GetUint16LE()
+	return int16(x), err
+}
+
+// GetInt16BE obtains a big-endian int16 from the current position in the buffer, regardless of the byte order
+// parameter.
+// This advances the position by 2.
+// Returns the read int16, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetInt16BE() (int16, error) {
+	x, err := t.GetUint16BE()
+	return int16(x), err
+}
+
+// GetUint32LE obtains a little-endian uint32 from the current position in the buffer, regardless of the byte order
+// parameter.
+// This advances the position by 4.
+// Returns the read uint32, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetUint32LE() (uint32, error) {
+	// Obtain the data to back our value
+	b, err := t.getFixedBytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+// GetUint32BE obtains a big-endian uint32 from the current position in the buffer, regardless of the byte order
+// parameter.
+// This advances the position by 4.
+// Returns the read uint32, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetUint32BE() (uint32, error) {
+	// Obtain the data to back our value
+	b, err := t.getFixedBytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(b), nil
+}
+
+// GetInt32LE obtains a little-endian int32 from the current position in the buffer, regardless of the byte order
+// parameter.
+// This advances the position by 4.
+// Returns the read int32, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetInt32LE() (int32, error) {
+	x, err := t.GetUint32LE()
+	return int32(x), err
+}
+
+// GetInt32BE obtains a big-endian int32 from the current position in the buffer, regardless of the byte order
+// parameter.
+// This advances the position by 4.
+// Returns the read int32, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetInt32BE() (int32, error) {
+	x, err := t.GetUint32BE()
+	return int32(x), err
+}
+
+// GetUint64LE obtains a little-endian uint64 from the current position in the buffer, regardless of the byte order
+// parameter.
+// This advances the position by 8.
+// Returns the read uint64, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetUint64LE() (uint64, error) {
+	// Obtain the data to back our value
+	b, err := t.getFixedBytes(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+// GetUint64BE obtains a big-endian uint64 from the current position in the buffer, regardless of the byte order
+// parameter.
+// This advances the position by 8.
+// Returns the read uint64, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetUint64BE() (uint64, error) {
+	// Obtain the data to back our value
+	b, err := t.getFixedBytes(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// GetInt64LE obtains a little-endian int64 from the current position in the buffer, regardless of the byte order
+// parameter.
+// This advances the position by 8.
+// Returns the read int64, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetInt64LE() (int64, error) {
+	x, err := t.GetUint64LE()
+	return int64(x), err
+}
+
+// GetInt64BE obtains a big-endian int64 from the current position in the buffer, regardless of the byte order
+// parameter.
+// This advances the position by 8.
+// Returns the read int64, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetInt64BE() (int64, error) {
+	x, err := t.GetUint64BE()
+	return int64(x), err
+}
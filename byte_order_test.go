@@ -0,0 +1,106 @@
+package go_fuzz_utils_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+func TestByteOrder(t *testing.T) {
+	// Create our type provider, which defaults to big-endian.
+	b := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09}
+	tp, err := go_fuzz_utils.NewTypeProvider(b)
+	assert.Nil(t, err)
+	assert.EqualValues(t, binary.BigEndian, tp.GetParamsByteOrder())
+	u32, err := tp.GetUint32()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x01020304, u32)
+
+	// Switch to little-endian and verify our getters follow it.
+	assert.Nil(t, tp.SetParamsByteOrder(binary.LittleEndian))
+	assert.EqualValues(t, binary.LittleEndian, tp.GetParamsByteOrder())
+	u16, err := tp.GetUint16()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x0605, u16)
+
+	// Ranges read their bytes in our byte order, followed by a byte of slack.
+	u16, err = tp.GetUint16InRange(0, 0xFFFE)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x0807, u16)
+
+	// Verify floats follow our byte order.
+	assert.Nil(t, tp.Reset())
+	f64, err := tp.GetFloat64()
+	assert.Nil(t, err)
+	assert.EqualValues(t, math.Float64frombits(0x0807060504030201), f64)
+
+	// Verify our explicit accessors ignore our byte order.
+	assert.Nil(t, tp.Reset())
+	i16, err := tp.GetInt16BE()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x0102, i16)
+	i16, err = tp.GetInt16LE()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x0403, i16)
+	u32, err = tp.GetUint32BE()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x05060708, u32)
+	assert.Nil(t, tp.Reset())
+	u32, err = tp.GetUint32LE()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x04030201, u32)
+	i32, err := tp.GetInt32BE()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x05060708, i32)
+	assert.Nil(t, tp.Reset())
+	i64, err := tp.GetInt64LE()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x0807060504030201, i64)
+	assert.Nil(t, tp.Reset())
+	u64, err := tp.GetUint64BE()
+	assert.Nil(t, err)
+	assert.EqualValues(t, uint64(0x0102030405060708), u64)
+
+	// Verify our explicit accessors return an error past the end of our data.
+	_, err = tp.GetUint64LE()
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrEndOfStream))
+
+	// Verify unsupported byte orders are rejected.
+	err = tp.SetParamsByteOrder(nil)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+}
+
+type byteOrderStruct struct {
+	U16  uint16
+	I32  int32
+	F64  float64
+	Port uint32 `fuzz:"min=1024,max=100000"`
+}
+
+func TestEncodeByteOrder(t *testing.T) {
+	// Encode a value using little-endian byte order.
+	value := byteOrderStruct{U16: 0x1234, I32: -2, F64: 1.5, Port: 0x10203}
+	tp, err := go_fuzz_utils.NewTypeProvider(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsByteOrder(binary.LittleEndian))
+	data, err := tp.Encode(&value)
+	assert.Nil(t, err)
+
+	// Verify our integers were encoded with their least significant byte first.
+	assert.EqualValues(t, []byte{0x34, 0x12, 0xFE, 0xFF, 0xFF, 0xFF}, data[:6])
+	// Our ranged integer is scaled into the bytes of its range, which are also least significant first, followed by a
+	// byte of slack: (0xA83090 * 98977 + 57 * 98977 / 256) >> 24 == 0x10203 - 1024.
+	assert.EqualValues(t, []byte{0x90, 0x30, 0xA8, 57}, data[14:])
+
+	// Fill a new value from our data and verify it matches.
+	tp, err = go_fuzz_utils.NewTypeProvider(data)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsByteOrder(binary.LittleEndian))
+	var filled byteOrderStruct
+	assert.Nil(t, tp.Fill(&filled))
+	assert.EqualValues(t, value, filled)
+}
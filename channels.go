@@ -0,0 +1,43 @@
+package go_fuzz_utils
+
+import "fmt"
+
+// GetParamsChanBounds obtains the minimum and maximum channel capacity parameters for use with Fill.
+func (t *TypeProvider) GetParamsChanBounds() (int, int) {
+	return t.chanMinSize, t.chanMaxSize
+}
+
+// SetParamsChanBounds sets the minimum and maximum channel capacity parameters for use with Fill. Channels are created
+// with a capacity within these bounds, and a number of filled elements up to their capacity are sent to them. A
+// capacity of zero produces an unbuffered channel with no elements.
+// Returns an error if any argument is negative or if a minSize is larger than maxSize.
+func (t *TypeProvider) SetParamsChanBounds(minSize int, maxSize int) error {
+	// Validate our parameters and set them accordingly
+	if minSize < 0 || maxSize < minSize {
+		return &InvalidParamError{Param: "channel bounds", Reason: fmt.Sprintf("min: %d, max: %d", minSize, maxSize)}
+	}
+	t.chanMinSize = minSize
+	t.chanMaxSize = maxSize
+	return nil
+}
+
+// GetParamsChanBiases obtains the channel bias parameters for use with Fill.
+// Returns two floats within range [0,1] indicating the probability of: nil channels, and channels being closed after
+// their elements are sent.
+func (t *TypeProvider) GetParamsChanBiases() (float32, float32) {
+	return t.chanNilBias, t.chanCloseBias
+}
+
+// SetParamsChanBiases sets the channel bias parameters for use with Fill, indicating the probability of nil channels,
+// and of channels being closed after their elements are sent. Elements sent to a closed channel can still be received
+// from it. The nil bias defaults to 0.05 and is also set by SetParamsBiasesCommon, while the close bias defaults to 0.
+// Returns an error if any bias value was not within the [0,1] range.
+func (t *TypeProvider) SetParamsChanBiases(nilBias float32, closeBias float32) error {
+	// Validate our parameters and set them accordingly
+	if nilBias < 0 || nilBias > 1 || closeBias < 0 || closeBias > 1 {
+		return &InvalidParamError{Param: "bias", Reason: "bias must be between [0,1]"}
+	}
+	t.chanNilBias = nilBias
+	t.chanCloseBias = closeBias
+	return nil
+}
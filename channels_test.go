@@ -0,0 +1,104 @@
+package go_fuzz_utils_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+// pipeline describes a struct with channel fields of each direction.
+type pipeline struct {
+	Jobs    chan uint8
+	Results <-chan uint8    `fuzz:"min=2,max=2"`
+	Done    chan<- struct{} `fuzz:"nilbias=1"`
+}
+
+func TestFillChannels(t *testing.T) {
+	// Create our type provider, closing half of our channels.
+	tp, err := go_fuzz_utils.NewTypeProvider([]byte{
+		0xFF, 0x03, 0x02, 0x0A, 0x0B, 0x00, // Jobs: not nil, capacity 3, 2 elements, closed
+		0xFF, 0x01, 0x0C, 0xFF, // Results: not nil, capacity 2 from its tag, 1 element, not closed
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsChanBiases(0.05, 0.5))
+
+	// Fill our struct.
+	var p pipeline
+	assert.Nil(t, tp.Fill(&p))
+
+	// Our buffered elements should be received before a closed channel reports it is closed.
+	assert.EqualValues(t, 3, cap(p.Jobs))
+	assert.EqualValues(t, 10, <-p.Jobs)
+	assert.EqualValues(t, 11, <-p.Jobs)
+	_, ok := <-p.Jobs
+	assert.False(t, ok)
+
+	// Receive-only channels are populated in the same way, and the nil bias of our tag applies.
+	assert.EqualValues(t, 2, cap(p.Results))
+	assert.EqualValues(t, 1, len(p.Results))
+	assert.EqualValues(t, 12, <-p.Results)
+	assert.Nil(t, p.Done)
+}
+
+func TestChannelParams(t *testing.T) {
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x100))
+	assert.Nil(t, err)
+
+	// Our default bounds and biases should match those of slices.
+	minSize, maxSize := tp.GetParamsChanBounds()
+	assert.EqualValues(t, 0, minSize)
+	assert.EqualValues(t, 15, maxSize)
+	nilBias, closeBias := tp.GetParamsChanBiases()
+	assert.EqualValues(t, 0.05, nilBias)
+	assert.EqualValues(t, 0, closeBias)
+
+	// The common nil bias applies to channels.
+	assert.Nil(t, tp.SetParamsBiasesCommon(1, 0))
+	nilBias, _ = tp.GetParamsChanBiases()
+	assert.EqualValues(t, 1, nilBias)
+	var ch chan int
+	assert.Nil(t, tp.Fill(&ch))
+	assert.Nil(t, ch)
+
+	// Invalid parameters return an error.
+	assert.True(t, errors.Is(tp.SetParamsChanBounds(-1, 1), go_fuzz_utils.ErrInvalidParam))
+	assert.True(t, errors.Is(tp.SetParamsChanBounds(2, 1), go_fuzz_utils.ErrInvalidParam))
+	assert.True(t, errors.Is(tp.SetParamsChanBiases(0, 1.5), go_fuzz_utils.ErrInvalidParam))
+}
+
+func TestEncodeChannels(t *testing.T) {
+	tp, err := go_fuzz_utils.NewTypeProvider(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsChanBiases(0.05, 0.5))
+
+	// Empty channels are encoded with their capacity and whether they are closed.
+	jobs := make(chan uint8, 3)
+	close(jobs)
+	value := pipeline{Jobs: jobs, Results: make(chan uint8, 2)}
+	data, err := tp.Encode(&value)
+	assert.Nil(t, err)
+	tp, err = go_fuzz_utils.NewTypeProvider(data)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsChanBiases(0.05, 0.5))
+	var filled pipeline
+	assert.Nil(t, tp.Fill(&filled))
+	assert.EqualValues(t, 3, cap(filled.Jobs))
+	_, ok := <-filled.Jobs
+	assert.False(t, ok)
+	assert.EqualValues(t, 2, cap(filled.Results))
+	assert.EqualValues(t, 0, len(filled.Results))
+	assert.Nil(t, filled.Done)
+
+	// Send-only channels can be encoded too, and encoding doesn't receive from any channel.
+	done := make(chan struct{}, 1)
+	var sendOnly chan<- struct{} = done
+	data, err = tp.Encode(&sendOnly)
+	assert.Nil(t, err)
+	var filledSendOnly chan<- struct{}
+	tp, err = go_fuzz_utils.NewTypeProvider(data)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.Fill(&filledSendOnly))
+	assert.EqualValues(t, 1, cap(filledSendOnly))
+}
@@ -0,0 +1,51 @@
+package go_fuzz_utils
+
+import (
+	"fmt"
+	"math"
+)
+
+// PickIndex obtains an index for a choice between n options from the current position in the buffer. This advances
+// the position as done by GetIntInRange, which reads no bytes if there is a single option.
+// Returns the chosen index in the range [0, n), or an error if n is not positive or the end of stream has been reached.
+func (t *TypeProvider) PickIndex(n int) (int, error) {
+	// Validate our parameters
+	if n <= 0 {
+		return 0, &InvalidParamError{Param: "option count", Reason: fmt.Sprintf("%d (must be positive)", n)}
+	}
+	return t.GetIntInRange(0, n-1)
+}
+
+// PickWeightedIndex obtains an index for a choice between options with the provided relative weights from the current
+// position in the buffer. Each index is chosen in proportion to its weight, so options with a weight of zero are never
+// chosen. This advances the position as done by GetUint64InRange for a range covering the sum of the weights.
+// Returns the chosen index, or an error if no weights are non-zero, the weights overflow, or the end of stream has been
+// reached.
+func (t *TypeProvider) PickWeightedIndex(weights []uint) (int, error) {
+	// Determine the sum of our weights, verifying it does not overflow.
+	total := uint64(0)
+	for _, weight := range weights {
+		if uint64(weight) > math.MaxUint64-total {
+			return 0, &InvalidParamError{Param: "weights", Reason: "sum of weights overflows"}
+		}
+		total += uint64(weight)
+	}
+	if total == 0 {
+		return 0, &InvalidParamError{Param: "weights", Reason: "at least one weight must be non-zero"}
+	}
+
+	// Obtain a value within our total weight, and determine the option whose cumulative weight covers it.
+	x, err := t.GetUint64InRange(0, total-1)
+	if err != nil {
+		return 0, err
+	}
+	for i, weight := range weights {
+		if x < uint64(weight) {
+			return i, nil
+		}
+		x -= uint64(weight)
+	}
+
+	// This is unreachable, as x is always less than the total weight.
+	panic("weighted choice exceeded total weight")
+}
@@ -0,0 +1,84 @@
+package go_fuzz_utils_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+func TestPickIndex(t *testing.T) {
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider([]byte{0x80, 0x00, 0x40, 0x00, 0x00, 0x80, 0x00})
+	assert.Nil(t, err)
+
+	// A choice between 5 options consumes a single byte and a byte of slack, which are scaled into the range.
+	i, err := tp.PickIndex(5)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, i) // 0x8000 * 5 >> 16
+
+	// A single option doesn't consume any data.
+	i, err = tp.PickIndex(1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, i)
+
+	// A choice between more than 256 options consumes two bytes and a byte of slack.
+	i, err = tp.PickIndex(1000)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 250, i) // 0x400000 * 1000 >> 24
+
+	// Options can be picked directly.
+	s, err := go_fuzz_utils.PickOne(tp, []string{"a", "b"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, "b", s)
+
+	// Reads past the end of our data return an error.
+	_, err = go_fuzz_utils.PickOne(tp, []string{"a", "b"})
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrEndOfStream))
+
+	// Invalid option counts return an error.
+	_, err = tp.PickIndex(0)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+	_, err = go_fuzz_utils.PickOne[int](tp, nil)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+}
+
+func TestPickWeighted(t *testing.T) {
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider([]byte{
+		0x00, 0x00, 0x20, 0x00, 0x40, 0x00, 0x80, 0x00, 0xFF, 0x00, 0x00, 0x00,
+	})
+	assert.Nil(t, err)
+
+	// Our options cover the ranges [0, 1), [1, 4) and [4, 10) of our total weight, which each value of two bytes is
+	// scaled into (0x0000 -> 0, 0x2000 -> 1, 0x4000 -> 2, 0x8000 -> 5, 0xFF00 -> 9).
+	options := []string{"rare", "common", "frequent"}
+	weights := []uint{1, 3, 6}
+	expected := []string{"rare", "common", "common", "frequent", "frequent"}
+	for _, e := range expected {
+		option, err := go_fuzz_utils.PickWeighted(tp, options, weights)
+		assert.Nil(t, err)
+		assert.EqualValues(t, e, option)
+	}
+
+	// Options with a weight of zero are never chosen.
+	i, err := tp.PickWeightedIndex([]uint{0, 5, 0})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, i)
+
+	// A total weight of one doesn't consume any data, so this succeeds despite our data being exhausted.
+	i, err = tp.PickWeightedIndex([]uint{0, 1, 0})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, i)
+
+	// Invalid weights return an error.
+	_, err = tp.PickWeightedIndex(nil)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+	_, err = tp.PickWeightedIndex([]uint{0, 0})
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+	_, err = tp.PickWeightedIndex([]uint{^uint(0), ^uint(0)})
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+	_, err = go_fuzz_utils.PickWeighted(tp, options, weights[:2])
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+}
@@ -0,0 +1,117 @@
+package go_fuzz_utils
+
+import (
+	"fmt"
+	"math"
+)
+
+// DecisionMode describes how a TypeProvider makes structural decisions, such as the sizes of strings, slices and maps,
+// and whether values are set as nil or skipped.
+type DecisionMode int
+
+const (
+	// DecisionModeData indicates structural decisions consume bytes from the input data, in line with the values being
+	// read. A small mutation of the input data therefore produces a small structural change, which suits
+	// coverage-guided fuzzing. This is the default mode.
+	DecisionModeData DecisionMode = iota
+	// DecisionModeSeeded indicates structural decisions are made by a random provider seeded from the first 8 bytes of
+	// the input data. This was the original behavior of TypeProvider and remains available for existing corpora.
+	DecisionModeSeeded
+	// DecisionModeTail indicates structural decisions consume bytes from the end of the input data, while values are
+	// read from the start, similar to LLVM's FuzzedDataProvider. A mutation of the bytes backing a value therefore does
+	// not shift any later structural decisions.
+	DecisionModeTail
+)
+
+// String obtains a human-readable name for the DecisionMode.
+func (m DecisionMode) String() string {
+	switch m {
+	case DecisionModeData:
+		return "data"
+	case DecisionModeSeeded:
+		return "seeded"
+	case DecisionModeTail:
+		return "tail"
+	default:
+		return fmt.Sprintf("DecisionMode(%d)", int(m))
+	}
+}
+
+// decisionWidth obtains the number of bytes needed to represent any value in the range [0, maxValue].
+func decisionWidth(maxValue uint64) int {
+	width := 0
+	for ; maxValue > 0; maxValue >>= 8 {
+		width++
+	}
+	return width
+}
+
+// getDecisionBytes obtains the requested number of bytes to make a structural decision from. When using
+// DecisionModeTail, these are read backwards from the end of the buffer, otherwise from the current position.
+// Reads past the end of the data return an error regardless of the exhaustion policy, so that callers consistently
+// fall back to their default decision, but are still recorded under ExhaustionPolicyZero.
+// Returns the requested bytes, or an error if the end of stream has been reached.
+func (t *TypeProvider) getDecisionBytes(length int) ([]byte, error) {
+	// If we're exhausted, there are no bytes to make our decision from.
+	if t.exhaust(length) {
+		return nil, &EndOfStreamError{Requested: length, Position: t.position, End: t.end, Length: len(t.data)}
+	}
+
+	// If we're not reading from the tail, read from our current position.
+	if t.decisionMode != DecisionModeTail {
+		return t.GetNBytes(length)
+	}
+
+	// Validate our boundaries
+	err := t.validateBounds(length)
+	if err != nil {
+		return nil, err
+	}
+
+	// Obtain our bytes in the order they're consumed from the end, and move our end back.
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = t.data[t.end-1-i]
+	}
+	t.end -= length
+	return b, nil
+}
+
+// getDecisionUint obtains an unsigned integer in the range [0, maxValue] from the input data, consuming only as many
+// bytes as needed to represent the range. If the end of stream has been reached, zero is returned.
+func (t *TypeProvider) getDecisionUint(maxValue uint64) uint64 {
+	// Obtain the bytes to back our value, defaulting to zero if there aren't enough left.
+	b, err := t.getDecisionBytes(decisionWidth(maxValue))
+	if err != nil {
+		return 0
+	}
+
+	// Construct our value from our bytes and wrap it into our range.
+	x := uint64(0)
+	for _, byteValue := range b {
+		x = (x << 8) | uint64(byteValue)
+	}
+	if maxValue == math.MaxUint64 {
+		return x
+	}
+	return x % (maxValue + 1)
+}
+
+// getDecisionBool obtains a boolean given a probability between 0 and 1 from the input data. Probabilities of zero
+// and one do not consume any data. Otherwise, a single byte is consumed, and the result is true if it is less than the
+// probability scaled to the range of a byte. If the end of stream has been reached, false is returned.
+func (t *TypeProvider) getDecisionBool(probability float32) bool {
+	// Certain outcomes do not need to consume any data.
+	if probability <= 0 {
+		return false
+	} else if probability >= 1 {
+		return true
+	}
+
+	// Obtain a byte and compare it against our probability.
+	b, err := t.getDecisionBytes(1)
+	if err != nil {
+		return false
+	}
+	return float32(b[0]) < probability*256
+}
@@ -0,0 +1,102 @@
+package go_fuzz_utils
+
+import (
+	"reflect"
+	"sync"
+)
+
+// recursiveContainers caches whether container types contain themselves without an intermediate struct, by type.
+var recursiveContainers sync.Map
+
+// GetParamsDepthCountContainers gets a parameter indicating whether pointers, slices, arrays, maps, channels and
+// interfaces count as a level of depth when filling values recursively using Fill.
+func (t *TypeProvider) GetParamsDepthCountContainers() bool {
+	return t.depthCountContainers
+}
+
+// SetParamsDepthCountContainers sets a parameter indicating whether pointers, slices, arrays, maps, channels and
+// interfaces count as a level of depth when filling values recursively using Fill, in addition to structs. This
+// defaults to false, in which case only structs and recursive container types which contain themselves without an
+// intermediate struct, such as `type Tree map[string]Tree` or `type L []L`, count as a level of depth, so every
+// recursive type is bounded by the depth limit either way.
+func (t *TypeProvider) SetParamsDepthCountContainers(countContainers bool) {
+	t.depthCountContainers = countContainers
+}
+
+// isRecursiveContainer determines whether a pointer, slice, array, map or channel type contains itself without an
+// intermediate struct, such as `type Tree map[string]Tree`. Recursive types which contain a struct are already bounded
+// by the depth limit, as structs always count as a level of depth.
+// Returns a boolean indicating whether the type is a recursive container type.
+func isRecursiveContainer(typ reflect.Type) bool {
+	// If we've already determined this for our type, use our cached result.
+	if recursive, ok := recursiveContainers.Load(typ); ok {
+		return recursive.(bool)
+	}
+
+	// Walk the types our type contains, stopping at structs and other kinds which are not containers.
+	visited := make(map[reflect.Type]bool)
+	var contains func(current reflect.Type) bool
+	contains = func(current reflect.Type) bool {
+		var elemTypes []reflect.Type
+		switch current.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan:
+			elemTypes = []reflect.Type{current.Elem()}
+		case reflect.Map:
+			elemTypes = []reflect.Type{current.Key(), current.Elem()}
+		}
+		for _, elemType := range elemTypes {
+			if elemType == typ {
+				return true
+			} else if !visited[elemType] {
+				visited[elemType] = true
+				if contains(elemType) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	recursive := contains(typ)
+	recursiveContainers.Store(typ, recursive)
+	return recursive
+}
+
+// countsDepth determines whether a container of the provided type counts as a level of depth, which is the case for
+// every container if containers count as a level of depth, or for recursive container types otherwise.
+func (t *TypeProvider) countsDepth(containerType reflect.Type) bool {
+	return t.depthCountContainers || isRecursiveContainer(containerType)
+}
+
+// elemDepth obtains the depth that the elements of a container of the provided type at the provided depth are filled
+// at, which is one level deeper if the container counts as a level of depth.
+func (t *TypeProvider) elemDepth(containerType reflect.Type, currentDepth int) int {
+	if t.countsDepth(containerType) {
+		return currentDepth + 1
+	}
+	return currentDepth
+}
+
+// depthExceeded determines whether a value of the provided type at the provided depth cannot be filled within the
+// depth limit, in which case Fill sets it to its zero value: nil for pointers, slices, maps, channels and interfaces,
+// or an empty struct or array. Structs at the depth limit cannot be filled, and neither can containers which count as
+// a level of depth. Pointers to structs which cannot be filled are nil rather than pointing to an empty struct.
+// Returns a boolean indicating whether the value cannot be filled within the depth limit.
+func (t *TypeProvider) depthExceeded(typ reflect.Type, currentDepth int) bool {
+	// If we have no depth limit, every value can be filled.
+	if t.depthLimit == 0 {
+		return false
+	}
+
+	// Determine if our value is beyond our depth limit based on its kind.
+	switch typ.Kind() {
+	case reflect.Struct:
+		return currentDepth >= t.depthLimit
+	case reflect.Ptr:
+		return (t.countsDepth(typ) && currentDepth >= t.depthLimit) ||
+			(typ.Elem().Kind() == reflect.Struct && t.elemDepth(typ, currentDepth) >= t.depthLimit)
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Chan, reflect.Interface:
+		return t.countsDepth(typ) && currentDepth >= t.depthLimit
+	default:
+		return false
+	}
+}
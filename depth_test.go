@@ -0,0 +1,146 @@
+package go_fuzz_utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+// tree describes a recursive map type which contains no structs.
+type tree map[string]tree
+
+// list describes a recursive slice type which contains no structs.
+type list []list
+
+// linkedNode describes a recursive struct type.
+type linkedNode struct {
+	Value uint8
+	Next  *linkedNode
+}
+
+// treeDepth obtains the number of nested levels of a tree.
+func treeDepth(tr tree) int {
+	if tr == nil {
+		return 0
+	}
+	depth := 1
+	for _, child := range tr {
+		if childDepth := treeDepth(child) + 1; childDepth > depth {
+			depth = childDepth
+		}
+	}
+	return depth
+}
+
+// listDepth obtains the number of nested levels of a list.
+func listDepth(l list) int {
+	if l == nil {
+		return 0
+	}
+	depth := 1
+	for _, child := range l {
+		if childDepth := listDepth(child) + 1; childDepth > depth {
+			depth = childDepth
+		}
+	}
+	return depth
+}
+
+func TestDepthCountContainers(t *testing.T) {
+	// Create our type provider, never producing nil values and counting containers as a level of depth.
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x10000))
+	assert.Nil(t, err)
+	assert.False(t, tp.GetParamsDepthCountContainers())
+	assert.Nil(t, tp.SetParamsBiasesCommon(0, 0))
+	assert.Nil(t, tp.SetParamsMapBounds(1, 2))
+	assert.Nil(t, tp.SetParamsSliceBounds(1, 2))
+	assert.Nil(t, tp.SetParamsDepthLimit(3))
+	tp.SetParamsDepthCountContainers(true)
+	assert.True(t, tp.GetParamsDepthCountContainers())
+
+	// Recursive maps and slices should be nested up to our depth limit, with nil values beyond it.
+	var tr tree
+	assert.Nil(t, tp.Fill(&tr))
+	assert.EqualValues(t, 3, treeDepth(tr))
+	var l list
+	assert.Nil(t, tp.Fill(&l))
+	assert.EqualValues(t, 3, listDepth(l))
+
+	// Pointers count as a level of depth too.
+	var n linkedNode
+	assert.Nil(t, tp.Fill(&n))
+	assert.NotNil(t, n.Next)
+	assert.Nil(t, n.Next.Next)
+
+	// Recursive values can be encoded, as long as they're within our depth limit.
+	value := tree{"a": tree{"b": tree{"x": nil}}, "c": tree{"d": tree{"e": nil, "f": nil}}}
+	data, err := tp.Encode(&value)
+	assert.Nil(t, err)
+	filledTp, err := go_fuzz_utils.NewTypeProvider(data)
+	assert.Nil(t, err)
+	assert.Nil(t, filledTp.SetParamsBiasesCommon(0, 0))
+	assert.Nil(t, filledTp.SetParamsMapBounds(1, 2))
+	assert.Nil(t, filledTp.SetParamsDepthLimit(3))
+	filledTp.SetParamsDepthCountContainers(true)
+	var filled tree
+	assert.Nil(t, filledTp.Fill(&filled))
+	assert.EqualValues(t, value, filled)
+	_, err = tp.Encode(&tree{"a": tree{"b": tree{"c": tree{"d": nil}}}})
+	assert.NotNil(t, err)
+}
+
+func TestDepthLimitZeroValues(t *testing.T) {
+	// Create our type provider, never producing nil values, with only structs counting as a level of depth.
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsBiasesCommon(0, 0))
+	assert.Nil(t, tp.SetParamsDepthLimit(2))
+
+	// Pointers to structs beyond our depth limit should be nil rather than pointing to an empty struct, replacing any
+	// existing value.
+	n := linkedNode{Next: &linkedNode{Next: &linkedNode{Value: 1}}}
+	assert.Nil(t, tp.Fill(&n))
+	assert.NotNil(t, n.Next)
+	assert.Nil(t, n.Next.Next)
+
+	// Structs beyond our depth limit should be set to empty structs.
+	type outer struct {
+		Inner struct {
+			Inner struct{ Value uint8 }
+		}
+	}
+	o := outer{}
+	o.Inner.Inner.Value = 1
+	assert.Nil(t, tp.Fill(&o))
+	assert.EqualValues(t, 0, o.Inner.Inner.Value)
+}
+
+func TestDepthRecursiveContainers(t *testing.T) {
+	// Create our type provider, never producing nil values, without counting every container as a level of depth.
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x10000))
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsBiasesCommon(0, 0))
+	assert.Nil(t, tp.SetParamsMapBounds(1, 2))
+	assert.Nil(t, tp.SetParamsSliceBounds(1, 2))
+	assert.Nil(t, tp.SetParamsDepthLimit(3))
+
+	// Recursive maps and slices should still be bounded by our depth limit.
+	var tr tree
+	assert.Nil(t, tp.Fill(&tr))
+	assert.EqualValues(t, 3, treeDepth(tr))
+	var l list
+	assert.Nil(t, tp.Fill(&l))
+	assert.EqualValues(t, 3, listDepth(l))
+
+	// Containers which are not recursive should not count as a level of depth.
+	type nested struct {
+		Inner struct {
+			Values [][]uint8
+		}
+	}
+	var n nested
+	assert.Nil(t, tp.Fill(&n))
+	assert.NotEmpty(t, n.Inner.Values)
+	assert.NotEmpty(t, n.Inner.Values[0])
+}
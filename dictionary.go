@@ -0,0 +1,226 @@
+package go_fuzz_utils
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"unicode/utf8"
+)
+
+// ParseDictionary parses a dictionary in the AFL/libFuzzer format, as accepted by the -dict flag of go-fuzz and
+// libFuzzer. Each line holds a quoted token, optionally preceded by a name and "=" (such as `kw_select="SELECT"`), where
+// names may carry an "@level" suffix. Tokens support the escape sequences \\, \" and \xNN. Blank lines and lines
+// starting with '#' are ignored.
+// Returns the tokens in the dictionary, or an error if a line could not be parsed.
+func ParseDictionary(data []byte) ([][]byte, error) {
+	var tokens [][]byte
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		// Skip blank lines and comments.
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		// Strip the name of the token if it has one, then parse the token.
+		if line[0] != '"' {
+			eq := bytes.IndexByte(line, '=')
+			if eq < 0 {
+				return nil, &InvalidParamError{Param: "dictionary", Reason: fmt.Sprintf("line %d: expected '='", i+1)}
+			}
+			line = bytes.TrimSpace(line[eq+1:])
+		}
+		token, err := parseDictionaryToken(line)
+		if err != nil {
+			return nil, &InvalidParamError{Param: "dictionary", Reason: fmt.Sprintf("line %d: %v", i+1, err)}
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+// parseDictionaryToken parses a quoted dictionary token, unescaping its contents.
+// Returns the unescaped token, or an error if the token is not properly quoted or contains an invalid escape sequence.
+func parseDictionaryToken(quoted []byte) ([]byte, error) {
+	// Verify our token is quoted.
+	if len(quoted) < 2 || quoted[0] != '"' || quoted[len(quoted)-1] != '"' {
+		return nil, fmt.Errorf("token %q is not quoted", quoted)
+	}
+
+	// Unescape each character between our quotes.
+	var token []byte
+	for i := 1; i < len(quoted)-1; i++ {
+		c := quoted[i]
+		if c != '\\' {
+			token = append(token, c)
+			continue
+		}
+
+		// Parse our escape sequence.
+		if i+1 < len(quoted)-1 && (quoted[i+1] == '\\' || quoted[i+1] == '"') {
+			token = append(token, quoted[i+1])
+			i++
+		} else if i+3 < len(quoted)-1 && quoted[i+1] == 'x' {
+			x, err := strconv.ParseUint(string(quoted[i+2:i+4]), 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid escape sequence %q", quoted[i:i+4])
+			}
+			token = append(token, byte(x))
+			i += 3
+		} else {
+			return nil, fmt.Errorf("invalid escape sequence in token %q", quoted)
+		}
+	}
+
+	// Verify our token is not empty.
+	if len(token) == 0 {
+		return nil, fmt.Errorf("token is empty")
+	}
+	return token, nil
+}
+
+// GetDictionary obtains the tokens which may be emitted or spliced into strings and byte slices.
+func (t *TypeProvider) GetDictionary() [][]byte {
+	return t.dictionary
+}
+
+// SetDictionary sets the tokens which may be emitted or spliced into strings and byte slices produced by GetString,
+// GetBytes and string values in Fill, given the dictionary bias. Dictionary tokens are used as they are, regardless of
+// the string mode. Providing no tokens clears the dictionary.
+// Returns an error if a token is empty.
+func (t *TypeProvider) SetDictionary(tokens [][]byte) error {
+	// Validate our parameters and copy our tokens, so later changes to them do not affect us.
+	dictionary := make([][]byte, 0, len(tokens))
+	for _, token := range tokens {
+		if len(token) == 0 {
+			return &InvalidParamError{Param: "dictionary", Reason: "tokens cannot be empty"}
+		}
+		dictionary = append(dictionary, append([]byte(nil), token...))
+	}
+	t.dictionary = dictionary
+	return nil
+}
+
+// LoadDictionary reads a dictionary in the AFL/libFuzzer format from the file at the provided path, as done by
+// ParseDictionary, and sets it as done by SetDictionary.
+// Returns an error if the file could not be read or parsed.
+func (t *TypeProvider) LoadDictionary(path string) error {
+	// Read and parse our dictionary.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	tokens, err := ParseDictionary(data)
+	if err != nil {
+		return err
+	}
+	return t.SetDictionary(tokens)
+}
+
+// GetParamsDictionaryBias obtains the probability of strings and byte slices being produced using the dictionary
+// (represented as a float between 0 and 1).
+func (t *TypeProvider) GetParamsDictionaryBias() float32 {
+	return t.dictionaryBias
+}
+
+// SetParamsDictionaryBias sets the probability of strings and byte slices being produced using the dictionary
+// (represented as a float between 0 and 1). When the dictionary is used, a value is equally likely to be a single
+// token, or to be read as usual with a token spliced into it at a random position, within the usual length bounds. The
+// bias defaults to 0.25, and no decisions are made if it is zero or no dictionary is set.
+// Returns an error if the bias is not within the [0,1] range.
+func (t *TypeProvider) SetParamsDictionaryBias(bias float32) error {
+	// Validate our parameters and set them accordingly
+	if bias < 0 || bias > 1 {
+		return &InvalidParamError{Param: "bias", Reason: "bias must be between [0,1]"}
+	}
+	t.dictionaryBias = bias
+	return nil
+}
+
+// usesDictionary indicates whether strings and byte slices may be produced using the dictionary.
+func (t *TypeProvider) usesDictionary() bool {
+	return len(t.dictionary) > 0 && t.dictionaryBias > 0
+}
+
+// tokenLength obtains the length of a dictionary token, in the unit used for strings if text is true, or in bytes
+// otherwise.
+func (t *TypeProvider) tokenLength(token []byte, text bool) int {
+	if text && t.stringMode != StringModeRaw && t.stringCountRunes {
+		return utf8.RuneCount(token)
+	}
+	return len(token)
+}
+
+// fittingTokens obtains the dictionary tokens whose length is within the provided range.
+// Returns the tokens which fit.
+func (t *TypeProvider) fittingTokens(minLength int, maxLength int, text bool) [][]byte {
+	var tokens [][]byte
+	for _, token := range t.dictionary {
+		if length := t.tokenLength(token, text); length >= minLength && length <= maxLength {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// getDictionaryValue determines whether a string (if text is true) or byte slice with a length in the provided range
+// should be produced using the dictionary, given the dictionary bias, and produces it if so. The value is either a
+// single token, or is read as usual with a token spliced into it.
+// Returns the produced value, a boolean indicating whether the dictionary was used, or an error if the end of stream
+// has been reached.
+func (t *TypeProvider) getDictionaryValue(minLength int, maxLength int, text bool) ([]byte, bool, error) {
+	// Determine if we should use our dictionary.
+	if !t.usesDictionary() || !t.getRandomBool(t.dictionaryBias) {
+		return nil, false, nil
+	}
+
+	// If we're not splicing, select a single token which fits our bounds.
+	if !t.getRandomBool(0.5) {
+		tokens := t.fittingTokens(minLength, maxLength, text)
+		if len(tokens) == 0 {
+			return nil, false, nil
+		}
+		return append([]byte(nil), tokens[t.getRandomSize(0, len(tokens)-1)]...), true, nil
+	}
+
+	// Otherwise select a token which leaves room for our bounds, and read the data to splice it into.
+	tokens := t.fittingTokens(0, maxLength, text)
+	if len(tokens) == 0 {
+		return nil, false, nil
+	}
+	token := tokens[t.getRandomSize(0, len(tokens)-1)]
+	tokenLength := t.tokenLength(token, text)
+	minLength -= tokenLength
+	if minLength < 0 {
+		minLength = 0
+	}
+	length := t.getRandomSize(minLength, maxLength-tokenLength)
+	var b []byte
+	var err error
+	if text {
+		var s string
+		s, err = t.readString(length)
+		b = []byte(s)
+	} else {
+		b, err = t.GetNBytes(length)
+	}
+	if err != nil {
+		return nil, true, err
+	}
+
+	// Determine the positions we can splice our token at, which are character boundaries for strings which are not raw.
+	positions := make([]int, 0, len(b)+1)
+	for i := 0; i <= len(b); i++ {
+		if i == len(b) || !text || t.stringMode == StringModeRaw || utf8.RuneStart(b[i]) {
+			positions = append(positions, i)
+		}
+	}
+
+	// Splice our token into our data at one of our positions.
+	position := positions[t.getRandomSize(0, len(positions)-1)]
+	result := make([]byte, 0, len(b)+len(token))
+	result = append(result, b[:position]...)
+	result = append(result, token...)
+	result = append(result, b[position:]...)
+	return result, true, nil
+}
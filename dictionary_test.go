@@ -0,0 +1,122 @@
+package go_fuzz_utils_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+func TestParseDictionary(t *testing.T) {
+	// Parse a dictionary with named, leveled and unnamed tokens, comments and escape sequences.
+	tokens, err := go_fuzz_utils.ParseDictionary([]byte(`
+# SQL keywords
+kw_select="SELECT"
+kw_from@1 = "FROM"
+"\x00\xFFA"
+"quote\"back\\"
+`))
+	assert.Nil(t, err)
+	assert.EqualValues(t, [][]byte{[]byte("SELECT"), []byte("FROM"), {0x00, 0xFF, 'A'}, []byte(`quote"back\`)}, tokens)
+
+	// Invalid lines return an error.
+	invalid := []string{`kw`, `kw="abc`, `abc`, `"\q"`, `""`, `"\x4"`, `"\xZZ"`}
+	for _, dict := range invalid {
+		_, err = go_fuzz_utils.ParseDictionary([]byte(dict))
+		assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam), "parsed invalid dictionary %q", dict)
+	}
+}
+
+func TestLoadDictionary(t *testing.T) {
+	// Write a dictionary to a file and load it.
+	path := filepath.Join(t.TempDir(), "sql.dict")
+	assert.Nil(t, os.WriteFile(path, []byte("kw1=\"SELECT\"\nkw2=\"FROM\"\n"), 0o644))
+	tp, err := go_fuzz_utils.NewTypeProvider(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.LoadDictionary(path))
+	assert.EqualValues(t, [][]byte{[]byte("SELECT"), []byte("FROM")}, tp.GetDictionary())
+
+	// Missing files return an error.
+	assert.NotNil(t, tp.LoadDictionary(filepath.Join(t.TempDir(), "missing.dict")))
+
+	// Empty tokens return an error, while no tokens clear the dictionary.
+	assert.True(t, errors.Is(tp.SetDictionary([][]byte{{}}), go_fuzz_utils.ErrInvalidParam))
+	assert.Nil(t, tp.SetDictionary(nil))
+	assert.Empty(t, tp.GetDictionary())
+}
+
+func TestDictionaryStrings(t *testing.T) {
+	// Create our type provider with a dictionary. Each value first decides whether to use the dictionary, then whether
+	// to splice a token rather than emit one.
+	tp, err := go_fuzz_utils.NewTypeProvider([]byte{
+		0x00, 0xFF, 0x01, // A single token, with index 1
+		0x00, 0x00, 0x00, 0x02, 'x', 'y', 0x01, // A spliced token with index 0, into 2 bytes at position 1
+		0xFF, 0x03, 'a', 'b', 'c', // A string without the dictionary, of length 3
+		0x00, 0xFF, 0x02, // A single token, with index 2
+	})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0.25, tp.GetParamsDictionaryBias())
+	assert.Nil(t, tp.SetDictionary([][]byte{[]byte("SELECT"), []byte("FROM"), []byte("a")}))
+
+	// Tokens are emitted and spliced into strings.
+	s, err := tp.GetString()
+	assert.Nil(t, err)
+	assert.EqualValues(t, "FROM", s)
+	s, err = tp.GetString()
+	assert.Nil(t, err)
+	assert.EqualValues(t, "xSELECTy", s)
+	s, err = tp.GetString()
+	assert.Nil(t, err)
+	assert.EqualValues(t, "abc", s)
+
+	// Tokens are also emitted as bytes.
+	b, err := tp.GetBytes()
+	assert.Nil(t, err)
+	assert.EqualValues(t, []byte("a"), b)
+
+	// Only tokens which fit our bounds are used.
+	assert.Nil(t, tp.SetParamsStringBounds(0, 4))
+	assert.Nil(t, tp.SetParamsDictionaryBias(1))
+	for i := 0; i < 100; i++ {
+		tp.Reset()
+		s, err = tp.GetString()
+		if err == nil {
+			assert.NotContains(t, s, "SELECT")
+			assert.LessOrEqual(t, len(s), 4)
+		}
+	}
+
+	// Invalid biases return an error.
+	assert.True(t, errors.Is(tp.SetParamsDictionaryBias(1.5), go_fuzz_utils.ErrInvalidParam))
+}
+
+func TestDictionaryFill(t *testing.T) {
+	// Create our type provider with a dictionary which is always used.
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetDictionary([][]byte{[]byte("Content-Type"), []byte("Accept")}))
+	assert.Nil(t, tp.SetParamsDictionaryBias(1))
+
+	// Every filled string should contain a token.
+	var headers []string
+	assert.Nil(t, tp.Fill(&headers))
+	assert.NotEmpty(t, headers)
+	for _, header := range headers {
+		assert.Regexp(t, "Content-Type|Accept", header)
+	}
+
+	// Strings which are tokens are encoded as tokens, and other strings are encoded as usual.
+	assert.Nil(t, tp.SetParamsDictionaryBias(0.25))
+	value := []string{"Accept", "other", "Content-Type"}
+	data, err := tp.Encode(&value)
+	assert.Nil(t, err)
+	tp, err = go_fuzz_utils.NewTypeProvider(data)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetDictionary([][]byte{[]byte("Content-Type"), []byte("Accept")}))
+	var filled []string
+	assert.Nil(t, tp.Fill(&filled))
+	assert.EqualValues(t, value, filled)
+}
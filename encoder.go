@@ -0,0 +1,616 @@
+package go_fuzz_utils
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// encoder produces fuzz input data which reproduces a given value when populated using Fill. It mirrors the
+// decisions made by fillValue, emitting the data each of them consumes.
+type encoder struct {
+	// t represents a TypeProvider holding the parameters used to encode values. It is used to simulate the structural
+	// decisions Fill will make when consuming the encoded data.
+	t *TypeProvider
+	// data represents the encoded data produced so far.
+	data []byte
+	// tail represents the encoded structural decisions produced so far when using DecisionModeTail, in the order they
+	// are consumed from the end of the data.
+	tail []byte
+	// visited represents the pointers, slices and maps encoded so far, so that values which are shared or which refer
+	// to themselves are not encoded more than once.
+	visited referenceSet
+	// walker represents the walker used to encode the fields and elements of values, in the same way Fill fills them.
+	// Struct fields which Fill does not populate must be zero values.
+	walker *walker
+}
+
+// Encode produces fuzz input data which causes Fill to reproduce the value at the provided pointer. The data is
+// produced for a new TypeProvider constructed from it with the same parameters and registered implementations as this
+// one, filling a zero value of the same type. The state of this TypeProvider is not changed.
+// When using DecisionModeSeeded, sizes and nil, skip and implementation choices are derived from the random seed at the
+// start of the data, so only values whose structure matches the choices derived from the seed can be encoded. Values
+// with fixed-size strings, slices and maps and a nil and skip bias of 0 or 1 can always be encoded. Values populated
+// by custom fill methods cannot be encoded, and channels can only be encoded while they hold no buffered elements.
+// Functions synthesized by Fill read their results when they're called, so those results are not encoded. Values which
+// share pointers, slices or maps, including cyclic ones, can only be encoded with an alias bias above zero, and are
+// otherwise reported as an error.
+// Returns the encoded data, or an error if the value could not be reproduced by Fill.
+func (t *TypeProvider) Encode(i interface{}) ([]byte, error) {
+	// Create an addressable copy of our value, so that unexported fields can be read in the same way they're filled.
+	src := reflect.Indirect(reflect.ValueOf(i))
+	if !src.IsValid() {
+		return nil, fmt.Errorf("could not encode value: a nil value was provided")
+	}
+	v := reflect.New(src.Type()).Elem()
+	v.Set(src)
+
+	// Create our encoder with a copy of our parameters. If our decisions are seeded, we create a random provider
+	// seeded from the start of our data.
+	sim := *t
+	sim.aliases = make(map[reflect.Type][]reflect.Value)
+	e := &encoder{t: &sim, visited: make(referenceSet)}
+	e.walker = &walker{t: e.t, visit: e.encodeValue, skip: e.encodeZero, mapKeys: true}
+	if e.t.decisionMode == DecisionModeSeeded {
+		e.data = make([]byte, 8)
+		e.t.randomProvider = rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(e.data))))
+	}
+
+	// Encode our value
+	err := e.encodeValue(v, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Append our structural decisions in reverse, as they are consumed from the end of the data.
+	for i := len(e.tail) - 1; i >= 0; i-- {
+		e.data = append(e.data, e.tail[i])
+	}
+	return e.data, nil
+}
+
+// encodeDecision encodes the bytes consumed by a structural decision, in the order they are consumed.
+func (e *encoder) encodeDecision(b ...byte) {
+	if e.t.decisionMode == DecisionModeTail {
+		e.tail = append(e.tail, b...)
+	} else {
+		e.data = append(e.data, b...)
+	}
+}
+
+// decideBool encodes a decision made by TypeProvider.getRandomBool with the provided probability, preferring the
+// provided outcome where possible.
+// Returns the outcome of the decision Fill will make.
+func (e *encoder) decideBool(preferred bool, probability float32) bool {
+	// If our decision is derived from our seed, we can only simulate it.
+	if e.t.decisionMode == DecisionModeSeeded {
+		return e.t.getRandomBool(probability)
+	}
+
+	// Certain outcomes do not consume any data.
+	if probability <= 0 {
+		return false
+	} else if probability >= 1 {
+		return true
+	}
+
+	// The lowest byte produces a true outcome, the highest produces a false one unless the probability is too high.
+	if preferred {
+		e.encodeDecision(0)
+		return true
+	}
+	e.encodeDecision(255)
+	return 255 < probability*256
+}
+
+// encodeBool encodes a decision made by TypeProvider.getRandomBool with the provided probability.
+// Returns an error if the decision could not be encoded.
+func (e *encoder) encodeBool(value bool, probability float32) error {
+	if e.decideBool(value, probability) != value {
+		return fmt.Errorf("decision (%v with probability %v) could not be encoded", value, probability)
+	}
+	return nil
+}
+
+// encodeSize encodes a decision made by TypeProvider.getRandomSize with the provided bounds.
+// Returns an error if the decision could not be encoded.
+func (e *encoder) encodeSize(size int, min int, max int) error {
+	// Verify our size is within our bounds.
+	if size < min || size > max {
+		return fmt.Errorf("size %d is not within bounds [%d, %d]", size, min, max)
+	}
+
+	// If our decision is derived from our seed, we can only verify it matches.
+	if e.t.decisionMode == DecisionModeSeeded {
+		if e.t.getRandomSize(min, max) != size {
+			return fmt.Errorf("size %d within bounds [%d, %d] does not match the seeded random provider", size, min, max)
+		}
+		return nil
+	}
+
+	// Encode our size relative to our minimum, using as many bytes as the range needs.
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(size-min))
+	e.encodeDecision(b[8-decisionWidth(uint64(max-min)):]...)
+	return nil
+}
+
+// encodeString encodes a string as produced by TypeProvider.getString with the provided length bounds, given the
+// string mode and dictionary.
+// Returns an error if the string could not be produced in the string mode.
+func (e *encoder) encodeString(s string, minLength int, maxLength int) error {
+	// If our dictionary may be used, encode our string as a single token if it is one, otherwise encode that it is not
+	// produced from our dictionary.
+	if e.t.usesDictionary() {
+		tokens := e.t.fittingTokens(minLength, maxLength, true)
+		index := -1
+		for i, token := range tokens {
+			if string(token) == s {
+				index = i
+				break
+			}
+		}
+		if e.decideBool(index >= 0, e.t.dictionaryBias) {
+			if err := e.encodeBool(false, 0.5); err != nil {
+				return err
+			}
+			// If no tokens fit our bounds, the string is read as usual.
+			if len(tokens) > 0 {
+				if index < 0 {
+					return fmt.Errorf("string %q must be produced from the dictionary, but is not a token", s)
+				}
+				return e.encodeSize(index, 0, len(tokens)-1)
+			}
+		}
+	}
+
+	// Encode our length in the unit our string mode counts it in.
+	length := len(s)
+	if e.t.stringMode != StringModeRaw && e.t.stringCountRunes {
+		length = utf8.RuneCountInString(s)
+	}
+	if err := e.encodeSize(length, minLength, maxLength); err != nil {
+		return err
+	}
+
+	// Raw strings are read directly from our bytes.
+	if e.t.stringMode == StringModeRaw {
+		e.data = append(e.data, s...)
+		return nil
+	}
+
+	// Encode each rune as it is read by TypeProvider.readRune, verifying it can be produced in our mode.
+	valid := utf8.ValidString(s)
+	for i, r := range s {
+		if e.t.stringMode == StringModeASCII {
+			valid = valid && r < utf8.RuneSelf
+		} else if e.t.stringMode == StringModePrintable {
+			valid = valid && unicode.IsPrint(r)
+		} else if e.t.stringMode == StringModeRunes {
+			// Runes are chosen from those in our set which fit the remaining length.
+			maxSize := utf8.UTFMax
+			if !e.t.stringCountRunes {
+				maxSize = len(s) - i
+			}
+			runes := fittingRunes(e.t.stringRunes, maxSize)
+			index := -1
+			for j := range runes {
+				if runes[j] == r {
+					index = j
+					break
+				}
+			}
+			if valid && index >= 0 {
+				e.encodeRange(uint64(index), uint64(len(runes)-1))
+				continue
+			}
+			valid = false
+		}
+		if !valid {
+			return fmt.Errorf("string %q cannot be produced in string mode %v", s, e.t.stringMode)
+		}
+		e.data = append(e.data, string(r)...)
+	}
+	return nil
+}
+
+// encodeFloat encodes a float of the provided kind as produced by Fill, given the float mode and special value bias.
+// Returns an error if the value could not be produced in the float mode.
+func (e *encoder) encodeFloat(kind reflect.Kind, f float64) error {
+	// Determine the bits and size of our value.
+	bits, size, unitBits := math.Float64bits(f), 8, 53
+	if kind == reflect.Float32 {
+		bits, size, unitBits = uint64(math.Float32bits(float32(f))), 4, 24
+	}
+
+	// Encode whether our value is produced as a special value, and if so, which one.
+	if e.t.floatSpecialBias > 0 {
+		index := indexOfSpecialFloat(f)
+		if e.decideBool(index >= 0, e.t.floatSpecialBias) {
+			if index < 0 {
+				return fmt.Errorf("value %v must be NaN or an infinity to be encoded", f)
+			}
+			return e.encodeSize(index, 0, len(specialFloats)-1)
+		}
+	}
+
+	// Values may be produced from the interesting values which can be produced in our float mode rather than read.
+	interesting, err := e.encodeInteresting(e.t.fillInterestingFloats(kind), kind, bits)
+	if interesting || err != nil {
+		return err
+	}
+
+	// Encode our value according to our float mode.
+	switch e.t.floatMode {
+	case FloatModeFinite:
+		// Finite values are read unchanged.
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("value %v cannot be produced in float mode %v", f, e.t.floatMode)
+		}
+	case FloatModeUnit:
+		// Unit values are read as the numerator of a fraction in the upper bits of our value.
+		numerator := math.Ldexp(f, unitBits)
+		if f < 0 || f >= 1 || math.Signbit(f) || numerator != math.Trunc(numerator) {
+			return fmt.Errorf("value %v cannot be produced in float mode %v", f, e.t.floatMode)
+		}
+		bits = uint64(numerator) << (size*8 - unitBits)
+	}
+	e.encodeUint(bits, size)
+	return nil
+}
+
+// encodeInteresting encodes a decision made by TypeProvider.selectInterestingValue with the provided table for a value
+// of the provided kind, represented by its bits, preferring to produce the value from the table if it is in it.
+// Returns a boolean indicating whether the value is produced from its table, or an error if the decision could not be
+// encoded.
+func (e *encoder) encodeInteresting(table []uint64, kind reflect.Kind, bits uint64) (bool, error) {
+	// If no decision is made, the value is always read.
+	if e.t.interestingValueBias <= 0 || len(table) == 0 {
+		return false, nil
+	}
+
+	// Encode whether our value is produced from its table, and if so, its index within it.
+	index := indexOfInteresting(table, bits)
+	if !e.decideBool(index >= 0, e.t.interestingValueBias) {
+		return false, nil
+	}
+	if index < 0 {
+		return true, fmt.Errorf("value with bits %#x of kind %v must be an interesting value to be encoded", bits, kind)
+	}
+	return true, e.encodeSize(index, 0, len(table)-1)
+}
+
+// encodeUint encodes an unsigned integer of the provided size in bytes, as read by the TypeProvider's getters.
+func (e *encoder) encodeUint(x uint64, size int) {
+	e.data = append(e.data, e.t.uintToBytes(x, size)...)
+}
+
+// encodeRange encodes an offset within a range spanning [0, span], as read by TypeProvider.GetUint64InRange.
+func (e *encoder) encodeRange(offset uint64, span uint64) {
+	// Ranges covering every value are read directly, and ranges of a single value aren't read at all.
+	if span == math.MaxUint64 {
+		e.encodeUint(offset, 8)
+		return
+	} else if span == 0 {
+		return
+	}
+
+	// Encode the smallest value which is scaled to our offset, followed by its byte of slack.
+	x, slack := scaleFromRange(offset, span)
+	e.encodeUint(x, decisionWidth(span))
+	e.data = append(e.data, slack)
+}
+
+// encodeAlias encodes whether a pointer, slice or map reuses a value created earlier, as decided by
+// TypeProvider.fillAlias, and which value it reuses if so.
+// Returns a boolean indicating whether the value was encoded as reusing another, or an error if the decision could not
+// be encoded.
+func (e *encoder) encodeAlias(v reflect.Value, constraints *fieldConstraints) (bool, error) {
+	// If there are no values which may be reused, no decision is made.
+	candidates := e.t.aliasCandidates(v, constraints)
+	if len(candidates) == 0 {
+		return false, nil
+	}
+
+	// Determine which value our value reuses, if any, and encode our decision.
+	index := -1
+	for i, candidate := range candidates {
+		if sameReference(candidate, v) {
+			index = i
+			break
+		}
+	}
+	if !e.decideBool(index >= 0, e.t.aliasBias) {
+		return false, nil
+	} else if index < 0 {
+		return false, fmt.Errorf("value of type %v must reuse a value created earlier", v.Type())
+	}
+	return true, e.encodeSize(index, 0, len(candidates)-1)
+}
+
+// encodeReference marks a pointer, slice or map as encoded. Fill can only produce a value which is shared with another,
+// or which refers to itself, by reusing a value created earlier.
+// Returns an error if the value was already encoded.
+func (e *encoder) encodeReference(v reflect.Value) error {
+	if e.visited.visit(v) {
+		return fmt.Errorf("value of type %v is shared or cyclic, but was not encoded as reusing a value created "+
+			"earlier (this requires an alias bias above zero)", v.Type())
+	}
+	return nil
+}
+
+// encodeZero verifies a value which Fill would not populate is a zero value.
+// Returns an error if the value is not a zero value.
+func (e *encoder) encodeZero(v reflect.Value, reason string) error {
+	if !v.IsZero() {
+		return fmt.Errorf("value of type %v must be a zero value as %s", v.Type(), reason)
+	}
+	return nil
+}
+
+// encodeValue encodes a value based on reflection, mirroring the decisions made by fillValue.
+// Returns an error if the value could not be reproduced by Fill.
+func (e *encoder) encodeValue(v reflect.Value, currentDepth int, constraints *fieldConstraints) error {
+	// Determine if this field will be skipped, in which case it must be a zero value. Zero values are encoded as
+	// skipped where possible, as constraints such as minimum lengths or ranges may otherwise prevent Fill producing them.
+	skipBias := constraints.getSkipBias(e.t.skipFieldBias)
+	if e.decideBool(skipBias > 0 && v.IsZero(), skipBias) {
+		return e.encodeZero(v, "it is skipped")
+	}
+
+	// Values populated by custom fill methods cannot be reproduced.
+	if e.t.hasCustomFill(v) {
+		return fmt.Errorf("could not encode value of type %v: it is populated by a custom fill method", v.Type())
+	}
+
+	// Values with numeric range constraints can only be produced by Fill if they're within the range.
+	if err := constraints.encodeValue(v); err != nil {
+		return err
+	}
+
+	// Values with integer range constraints are read as an offset from the minimum within the span of the range.
+	if constraints.hasIntegerRange(v) {
+		if v.CanInt() {
+			span := uint64(constraints.maxInt) - uint64(constraints.minInt)
+			e.encodeRange(uint64(v.Int())-uint64(constraints.minInt), span)
+		} else {
+			e.encodeRange(v.Uint()-constraints.minUint, constraints.maxUint-constraints.minUint)
+		}
+		return nil
+	}
+
+	// Integer values may be produced from a table of interesting values rather than read. Floats make this decision
+	// depending on their float mode, in encodeFloat.
+	if bits, ok := interestingBits(v); ok && !v.CanFloat() {
+		interesting, err := e.encodeInteresting(e.t.interestingValues[v.Kind()], v.Kind(), bits)
+		if interesting || err != nil {
+			return err
+		}
+	}
+
+	// Values read as varints are encoded in as few bytes as their value needs.
+	if e.t.varintIntegers && isVarintKind(v.Kind()) {
+		b := make([]byte, binary.MaxVarintLen64)
+		if v.CanInt() {
+			e.data = append(e.data, b[:binary.PutVarint(b, v.Int())]...)
+		} else {
+			e.data = append(e.data, b[:binary.PutUvarint(b, v.Uint())]...)
+		}
+		return nil
+	}
+
+	// Values which can't be filled within our depth limit must reuse a value created earlier, or be zero values.
+	if e.t.depthExceeded(v.Type(), currentDepth) {
+		if reused, err := e.encodeAlias(v, constraints); reused || err != nil {
+			return err
+		}
+		return e.encodeZero(v, "it is beyond the depth limit")
+	}
+
+	// Determine how to encode our value based on its type.
+	if v.Kind() == reflect.Bool {
+		// GetBool returns true for even bytes.
+		if v.Bool() {
+			e.data = append(e.data, 0)
+		} else {
+			e.data = append(e.data, 1)
+		}
+	} else if v.Kind() == reflect.Int8 || v.Kind() == reflect.Int16 || v.Kind() == reflect.Int32 ||
+		v.Kind() == reflect.Int64 {
+		e.encodeUint(uint64(v.Int()), v.Type().Bits()/8)
+	} else if v.Kind() == reflect.Uint8 || v.Kind() == reflect.Uint16 || v.Kind() == reflect.Uint32 ||
+		v.Kind() == reflect.Uint64 {
+		e.encodeUint(v.Uint(), v.Type().Bits()/8)
+	} else if v.Kind() == reflect.Int {
+		// Architecture-dependent widths are always read as 64-bit values.
+		e.encodeUint(uint64(v.Int()), 8)
+	} else if v.Kind() == reflect.Uint {
+		e.encodeUint(v.Uint(), 8)
+	} else if v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64 {
+		if err := e.encodeFloat(v.Kind(), v.Float()); err != nil {
+			return err
+		}
+	} else if v.Kind() == reflect.Complex64 || v.Kind() == reflect.Complex128 {
+		// Each part is read as a float of half the complex value's width.
+		partKind := reflect.Float64
+		if v.Kind() == reflect.Complex64 {
+			partKind = reflect.Float32
+		}
+		if err := e.encodeFloat(partKind, real(v.Complex())); err != nil {
+			return err
+		}
+		if err := e.encodeFloat(partKind, imag(v.Complex())); err != nil {
+			return err
+		}
+	} else if v.Kind() == reflect.String {
+		// Strings generated from a pattern depend on how the pattern is walked, so they cannot be reproduced.
+		if constraints.getPattern() != nil {
+			return fmt.Errorf("could not encode value of type %v: it is generated from a pattern", v.Type())
+		}
+		minLength, maxLength := constraints.getLengthBounds(e.t.stringMinLength, e.t.stringMaxLength)
+		if err := e.encodeString(v.String(), minLength, maxLength); err != nil {
+			return err
+		}
+	} else if v.Kind() == reflect.Slice {
+		// Encode whether the slice is nil.
+		if err := e.encodeBool(v.IsNil(), constraints.getNilBias(e.t.sliceNilBias)); err != nil {
+			return err
+		}
+		if !v.IsNil() {
+			// Encode whether the slice reuses one created earlier, otherwise it may be reused from now on.
+			if aliased, err := e.encodeAlias(v, constraints); aliased || err != nil {
+				return err
+			} else if err := e.encodeReference(v); err != nil {
+				return err
+			}
+			e.t.addAlias(v)
+
+			// Encode our size
+			minSize, maxSize := constraints.getLengthBounds(e.t.sliceMinSize, e.t.sliceMaxSize)
+			if err := e.encodeSize(v.Len(), minSize, maxSize); err != nil {
+				return err
+			}
+
+			// Byte slices are read all at once, other slices have each element encoded.
+			if v.Type().Elem().Kind() == reflect.Uint8 {
+				e.data = append(e.data, v.Bytes()...)
+			} else if err := e.walker.walkElements(v, currentDepth); err != nil {
+				return err
+			}
+		}
+	} else if v.Kind() == reflect.Map {
+		// Encode whether the map is nil.
+		if err := e.encodeBool(v.IsNil(), constraints.getNilBias(e.t.mapNilBias)); err != nil {
+			return err
+		}
+		if !v.IsNil() {
+			// Encode whether the map reuses one created earlier, otherwise it may be reused from now on.
+			if aliased, err := e.encodeAlias(v, constraints); aliased || err != nil {
+				return err
+			} else if err := e.encodeReference(v); err != nil {
+				return err
+			}
+			e.t.addAlias(v)
+
+			// Encode our size
+			minSize, maxSize := constraints.getLengthBounds(e.t.mapMinSize, e.t.mapMaxSize)
+			if err := e.encodeSize(v.Len(), minSize, maxSize); err != nil {
+				return err
+			}
+
+			// Encode each key-value pair, using addressable copies of them, in the order of their keys so our encoded
+			// data is reproducible.
+			if err := e.walker.walkMap(v, currentDepth); err != nil {
+				return err
+			}
+		}
+	} else if v.Kind() == reflect.Chan {
+		// Encode whether the channel is nil.
+		if err := e.encodeBool(v.IsNil(), constraints.getNilBias(e.t.chanNilBias)); err != nil {
+			return err
+		}
+		if !v.IsNil() {
+			// Receiving elements would change the channel, so only empty channels can be encoded.
+			if v.Len() > 0 {
+				return fmt.Errorf("channels with buffered elements cannot be encoded")
+			}
+
+			// Encode our capacity, followed by our element count.
+			minSize, maxSize := constraints.getLengthBounds(e.t.chanMinSize, e.t.chanMaxSize)
+			if err := e.encodeSize(v.Cap(), minSize, maxSize); err != nil {
+				return err
+			}
+			if err := e.encodeSize(0, 0, v.Cap()); err != nil {
+				return err
+			}
+
+			// Determine whether our channel is closed by attempting to receive from it, which does not block or
+			// receive anything as it is empty. Send-only channels are viewed as bidirectional to do so.
+			ch := v
+			if v.Type().ChanDir()&reflect.RecvDir == 0 {
+				if !v.CanAddr() {
+					return fmt.Errorf("send-only channels which are not addressable cannot be encoded")
+				}
+				ch = reflect.NewAt(reflect.ChanOf(reflect.BothDir, v.Type().Elem()), unsafe.Pointer(v.UnsafeAddr())).Elem()
+			}
+			received, _ := ch.TryRecv()
+			if err := e.encodeBool(received.IsValid(), e.t.chanCloseBias); err != nil {
+				return err
+			}
+		}
+	} else if v.Kind() == reflect.Func {
+		// Encode whether the function is nil. Results of synthesized functions are read when they're called, so any
+		// function is encoded as one which is synthesized.
+		if err := e.encodeBool(v.IsNil(), constraints.getNilBias(e.t.funcNilBias)); err != nil {
+			return err
+		}
+	} else if v.Kind() == reflect.Ptr {
+		// Encode whether the pointer is nil, then the pointer it reuses or the value it points to.
+		if err := e.encodeBool(v.IsNil(), constraints.getNilBias(e.t.ptrNilBias)); err != nil {
+			return err
+		}
+		if !v.IsNil() {
+			// Encode whether the pointer reuses one created earlier, otherwise it may be reused from now on.
+			if aliased, err := e.encodeAlias(v, constraints); aliased || err != nil {
+				return err
+			} else if err := e.encodeReference(v); err != nil {
+				return err
+			}
+			e.t.addAlias(v)
+			if err := e.walker.walkPointer(v, currentDepth, constraints); err != nil {
+				return err
+			}
+		}
+	} else if v.Kind() == reflect.Interface {
+		// Interfaces without registered implementations are not populated.
+		implTypes := e.t.implementations[v.Type()]
+		if len(implTypes) == 0 {
+			return e.encodeZero(v, "no implementations are registered for it")
+		}
+
+		// Encode whether the interface is nil.
+		if err := e.encodeBool(v.IsNil(), constraints.getNilBias(e.t.ptrNilBias)); err != nil {
+			return err
+		}
+		if !v.IsNil() {
+			// Encode the index of the implementation of our value.
+			implIndex := -1
+			for i, implType := range implTypes {
+				if implType == v.Elem().Type() {
+					implIndex = i
+					break
+				}
+			}
+			if implIndex < 0 {
+				return fmt.Errorf("could not encode value of type %v: it is not registered as an implementation of %v", v.Elem().Type(), v.Type())
+			}
+			if err := e.encodeSize(implIndex, 0, len(implTypes)-1); err != nil {
+				return err
+			}
+
+			// Encode an addressable copy of our concrete value.
+			if err := e.walker.walkInterface(v, currentDepth, constraints); err != nil {
+				return err
+			}
+		}
+	} else if v.Kind() == reflect.Array {
+		// Encode each element.
+		if err := e.walker.walkElements(v, currentDepth); err != nil {
+			return err
+		}
+	} else if v.Kind() == reflect.Struct {
+		// Encode every field Fill would populate. Fields it does not populate must be zero values.
+		if err := e.walker.walkStruct(v, currentDepth); err != nil {
+			return err
+		}
+	} else {
+		// Any other values are not populated by Fill.
+		return e.encodeZero(v, "it is not populated by Fill")
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package go_fuzz_utils_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+type encodedInner struct {
+	S   string
+	Arr [2]int16
+	p   *uint32
+}
+
+type encodedStruct struct {
+	B     bool
+	I8    int8
+	I16   int16
+	I32   int32
+	I64   int64
+	I     int
+	U8    uint8
+	U16   uint16
+	U32   uint32
+	U64   uint64
+	U     uint
+	F32   float32
+	F64   float64
+	C64   complex64
+	C128  complex128
+	S     string
+	Bytes []byte
+	Ints  []int32
+	M     map[string]uint16
+	P     *encodedInner
+	Shape shape
+	Arr   [3]bool
+	Inner encodedInner
+	inner encodedInner
+	Age   uint8  `fuzz:"min=18,max=99"`
+	Name  string `fuzz:"min=5,max=5"`
+	Never string `fuzz:"-"`
+	Ch    chan int
+}
+
+// newEncodingTypeProvider creates a TypeProvider with parameters producing fixed-size values, so that the structure of
+// values does not depend on the seeded random provider.
+func newEncodingTypeProvider(t *testing.T, b []byte, mode go_fuzz_utils.DecisionMode) *go_fuzz_utils.TypeProvider {
+	tp, err := go_fuzz_utils.NewTypeProvider(b)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsDecisionMode(mode))
+	assert.Nil(t, tp.SetParamsBiasesCommon(0, 0))
+	assert.Nil(t, tp.SetParamsStringBounds(3, 3))
+	assert.Nil(t, tp.SetParamsSliceBounds(2, 2))
+	assert.Nil(t, tp.SetParamsMapBounds(1, 1))
+	assert.Nil(t, tp.SetParamsChanBounds(0, 0))
+	assert.Nil(t, go_fuzz_utils.RegisterImplementationsOf[shape](tp, circle{}, &square{}))
+	return tp
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	modes := []go_fuzz_utils.DecisionMode{go_fuzz_utils.DecisionModeData, go_fuzz_utils.DecisionModeSeeded, go_fuzz_utils.DecisionModeTail}
+	for _, mode := range modes {
+		t.Run(mode.String(), func(t *testing.T) {
+			testEncodeRoundTrip(t, mode)
+		})
+	}
+}
+
+func testEncodeRoundTrip(t *testing.T, mode go_fuzz_utils.DecisionMode) {
+	// Create a value covering every kind Fill populates.
+	u32, u32Inner, u32Private := uint32(0xDEADBEEF), uint32(1), uint32(2)
+	value := encodedStruct{
+		B: true, I8: -8, I16: -16, I32: -32, I64: math.MinInt64, I: -1,
+		U8: 8, U16: 16, U32: 32, U64: math.MaxUint64, U: 7,
+		F32: 3.5, F64: math.Inf(-1), C64: complex(1, -2), C128: complex(math.MaxFloat64, 0.25),
+		S:     "abc",
+		Bytes: []byte{0, 255},
+		Ints:  []int32{1, -1},
+		M:     map[string]uint16{"key": 0xFFFF},
+		P:     &encodedInner{S: "ptr", Arr: [2]int16{1, 2}, p: &u32},
+		Shape: &square{Side: 4, Name: "sqr"},
+		Arr:   [3]bool{true, false, true},
+		Inner: encodedInner{S: "in1", p: &u32Inner},
+		inner: encodedInner{S: "in2", Arr: [2]int16{-1, -2}, p: &u32Private},
+		Age:   42,
+		Name:  "fives",
+		Ch:    make(chan int),
+	}
+
+	// Encode our value and fill a new value from the encoded data.
+	data, err := newEncodingTypeProvider(t, generateTestData(8), mode).Encode(&value)
+	assert.Nil(t, err)
+	tp := newEncodingTypeProvider(t, data, mode)
+	var filled encodedStruct
+	assert.Nil(t, tp.Fill(&filled))
+	assert.NotNil(t, filled.Ch)
+	assert.EqualValues(t, 0, cap(filled.Ch))
+	filled.Ch = value.Ch
+	assert.EqualValues(t, value, filled)
+
+	// Encode a value with nil pointers, slices, maps and interfaces, using a full nil bias.
+	nilValue := encodedStruct{S: "nil", Name: "zeros", Age: 18, P: nil, Inner: encodedInner{S: "inn"}, inner: encodedInner{S: "inn"}}
+	tp = newEncodingTypeProvider(t, generateTestData(8), mode)
+	assert.Nil(t, tp.SetParamsBiasesCommon(1, 0))
+	data, err = tp.Encode(&nilValue)
+	assert.Nil(t, err)
+	tp = newEncodingTypeProvider(t, data, mode)
+	assert.Nil(t, tp.SetParamsBiasesCommon(1, 0))
+	filled = encodedStruct{}
+	assert.Nil(t, tp.Fill(&filled))
+	assert.EqualValues(t, nilValue, filled)
+
+	// Encode a basic value.
+	f64 := math.Copysign(0, -1)
+	data, err = newEncodingTypeProvider(t, generateTestData(8), mode).Encode(&f64)
+	assert.Nil(t, err)
+	var filledF64 float64
+	assert.Nil(t, newEncodingTypeProvider(t, data, mode).Fill(&filledF64))
+	assert.EqualValues(t, math.Float64bits(f64), math.Float64bits(filledF64))
+}
+
+func TestEncodeErrors(t *testing.T) {
+	tp := newEncodingTypeProvider(t, generateTestData(8), go_fuzz_utils.DecisionModeData)
+
+	// Values which do not match our size parameters cannot be encoded.
+	_, err := tp.Encode(&[]int{1, 2, 3})
+	assert.NotNil(t, err)
+	_, err = tp.Encode(&map[int]int{})
+	assert.NotNil(t, err)
+
+	// Nil values cannot be encoded with a zero nil bias.
+	var nilSlice []int
+	_, err = tp.Encode(&nilSlice)
+	assert.NotNil(t, err)
+
+	// Values outside of their field constraints cannot be encoded.
+	_, err = tp.Encode(&encodedStruct{S: "abc", Name: "fives", Age: 17})
+	assert.NotNil(t, err)
+
+	// Values which Fill does not populate must be zero values. Errors describe the path to the value, as Fill does.
+	never := struct {
+		U8    uint8
+		Never string `fuzz:"-"`
+	}{U8: 1, Never: "x"}
+	_, err = tp.Encode(&never)
+	var fillErr *go_fuzz_utils.FillError
+	assert.True(t, errors.As(err, &fillErr))
+	assert.EqualValues(t, ".Never", fillErr.Path)
+
+	// Channels with buffered elements cannot be encoded.
+	ch := make(chan int, 1)
+	ch <- 1
+	_, err = tp.Encode(&encodedStruct{S: "abc", Name: "fives", Age: 18, Ch: ch})
+	assert.NotNil(t, err)
+	assert.EqualValues(t, 1, len(ch))
+
+	// Values populated by custom fill methods cannot be encoded.
+	id := validatedID("id-1")
+	_, err = tp.Encode(&id)
+	assert.NotNil(t, err)
+	assert.Nil(t, tp.RegisterFiller(reflect.TypeOf(0), func(tp *go_fuzz_utils.TypeProvider, v reflect.Value) error {
+		return nil
+	}))
+	x := 0
+	_, err = tp.Encode(&x)
+	assert.NotNil(t, err)
+
+	// Implementations which are not registered cannot be encoded.
+	var s shape = &otherShape{}
+	_, err = tp.Encode(&s)
+	assert.NotNil(t, err)
+}
+
+func TestEncodeDataDecisions(t *testing.T) {
+	for _, mode := range []go_fuzz_utils.DecisionMode{go_fuzz_utils.DecisionModeData, go_fuzz_utils.DecisionModeTail} {
+		t.Run(mode.String(), func(t *testing.T) {
+			testEncodeDataDecisions(t, mode)
+		})
+	}
+}
+
+func testEncodeDataDecisions(t *testing.T, mode go_fuzz_utils.DecisionMode) {
+	// Create a type provider with default parameters, which makes decisions from the data.
+	tp, err := go_fuzz_utils.NewTypeProvider(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsDecisionMode(mode))
+	assert.Nil(t, tp.SetParamsBiasesCommon(0.5, 0.1))
+	assert.Nil(t, tp.SetParamsSliceBounds(0, 300))
+	assert.Nil(t, go_fuzz_utils.RegisterImplementationsOf[shape](tp, circle{}, &square{}))
+
+	// Create a value of varying sizes, nil values and implementations, which can only be encoded when decisions are
+	// made from the data.
+	value := shapeStruct{
+		Shape:  circle{Radius: 2},
+		Shapes: []shape{nil, &square{Side: 3, Name: "a"}, circle{}, nil},
+	}
+	for i := 0; i < 256; i++ {
+		value.Shapes = append(value.Shapes, &square{Side: uint16(i)})
+	}
+
+	// Encode our value and fill a new value from the encoded data.
+	data, err := tp.Encode(&value)
+	assert.Nil(t, err)
+	tp, err = go_fuzz_utils.NewTypeProvider(data)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsDecisionMode(mode))
+	assert.Nil(t, tp.SetParamsBiasesCommon(0.5, 0.1))
+	assert.Nil(t, tp.SetParamsSliceBounds(0, 300))
+	assert.Nil(t, go_fuzz_utils.RegisterImplementationsOf[shape](tp, circle{}, &square{}))
+	var filled shapeStruct
+	assert.Nil(t, tp.Fill(&filled))
+	assert.EqualValues(t, value, filled)
+
+	// Encoding a non-nil value with a full nil bias should fail.
+	assert.Nil(t, tp.SetParamsBiasesCommon(1, 0))
+	_, err = tp.Encode(&value)
+	assert.NotNil(t, err)
+}
+
+func TestEncodeMapOrder(t *testing.T) {
+	// Maps should be encoded in the order of their keys, so the same value always produces the same data.
+	tp, err := go_fuzz_utils.NewTypeProvider(nil)
+	assert.Nil(t, err)
+	value := map[string]uint16{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6, "g": 7, "h": 8}
+	data, err := tp.Encode(&value)
+	assert.Nil(t, err)
+	for i := 0; i < 20; i++ {
+		other, err := tp.Encode(&value)
+		assert.Nil(t, err)
+		assert.EqualValues(t, data, other)
+	}
+}
+
+func TestEncodeSkippedZeroValues(t *testing.T) {
+	// Zero values outside of their field constraints can only be filled by skipping them.
+	type constrainedStruct struct {
+		Age  uint8   `fuzz:"min=18,max=99"`
+		Name string  `fuzz:"min=5,max=5"`
+		Ints []int32 `fuzz:"min=2,max=4"`
+	}
+	value := constrainedStruct{Age: 42}
+
+	// Encoding them should fail without a skip bias, and succeed with one.
+	tp := newEncodingTypeProvider(t, generateTestData(8), go_fuzz_utils.DecisionModeData)
+	_, err := tp.Encode(&value)
+	assert.NotNil(t, err)
+	assert.Nil(t, tp.SetParamsBiasesCommon(0, 0.5))
+	data, err := tp.Encode(&value)
+	assert.Nil(t, err)
+
+	// Fill a new value from the encoded data.
+	tp = newEncodingTypeProvider(t, data, go_fuzz_utils.DecisionModeData)
+	assert.Nil(t, tp.SetParamsBiasesCommon(0, 0.5))
+	var filled constrainedStruct
+	assert.Nil(t, tp.Fill(&filled))
+	assert.EqualValues(t, value, filled)
+}
+
+type otherShape struct{}
+
+func (o *otherShape) Area() float64 { return 0 }
@@ -0,0 +1,85 @@
+package go_fuzz_utils
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrEndOfStream indicates a read could not be satisfied by the data remaining in a TypeProvider. Errors returned
+	// for such reads match it when using errors.Is, and can be inspected further as an *EndOfStreamError.
+	ErrEndOfStream = errors.New("end of stream reached")
+	// ErrInvalidParam indicates an invalid parameter was provided to a TypeProvider. Errors returned for such parameters
+	// match it when using errors.Is, and can be inspected further as an *InvalidParamError.
+	ErrInvalidParam = errors.New("invalid parameter")
+)
+
+// EndOfStreamError describes a read which could not be satisfied by the data remaining in a TypeProvider.
+type EndOfStreamError struct {
+	// Requested describes the number of bytes which were requested.
+	Requested int
+	// Position describes the offset into the data which the read started at.
+	Position int
+	// End describes the offset into the data which data could be read up to.
+	End int
+	// Length describes the length of the data.
+	Length int
+}
+
+// Error obtains a description of the EndOfStreamError.
+func (e *EndOfStreamError) Error() string {
+	return fmt.Sprintf("%v: could not read %d bytes (position: %d / end: %d / length: %d)", ErrEndOfStream,
+		e.Requested, e.Position, e.End, e.Length)
+}
+
+// Is indicates whether the provided target is ErrEndOfStream, for use with errors.Is.
+func (e *EndOfStreamError) Is(target error) bool {
+	return target == ErrEndOfStream
+}
+
+// InvalidParamError describes an invalid parameter provided to a TypeProvider.
+type InvalidParamError struct {
+	// Param describes the parameter which was invalid.
+	Param string
+	// Reason describes why the parameter was invalid.
+	Reason string
+}
+
+// Error obtains a description of the InvalidParamError.
+func (e *InvalidParamError) Error() string {
+	return fmt.Sprintf("invalid %s provided: %s", e.Param, e.Reason)
+}
+
+// Is indicates whether the provided target is ErrInvalidParam, for use with errors.Is.
+func (e *InvalidParamError) Is(target error) bool {
+	return target == ErrInvalidParam
+}
+
+// FillError describes an error encountered while populating a value nested within the value provided to Fill.
+type FillError struct {
+	// Path describes the location of the value where the error occurred, relative to the value provided to Fill. It is
+	// made up of struct fields (".Name"), slice and array indices ("[2]"), and map entries ("[key 2]" or "[value 2]").
+	Path string
+	// Err describes the underlying error.
+	Err error
+}
+
+// Error obtains a description of the FillError.
+func (e *FillError) Error() string {
+	return fmt.Sprintf("could not fill %s: %v", e.Path, e.Err)
+}
+
+// Unwrap obtains the underlying error of the FillError, for use with errors.Is and errors.As.
+func (e *FillError) Unwrap() error {
+	return e.Err
+}
+
+// wrapFillError wraps an error encountered while populating a nested value with the provided path segment describing
+// where the nested value is located. If the error already is a FillError, the segment is prepended to its path.
+// Returns the wrapped error.
+func wrapFillError(err error, segment string) error {
+	if fillErr, ok := err.(*FillError); ok {
+		return &FillError{Path: segment + fillErr.Path, Err: fillErr.Err}
+	}
+	return &FillError{Path: segment, Err: err}
+}
@@ -0,0 +1,112 @@
+package go_fuzz_utils_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+func TestEndOfStreamErrors(t *testing.T) {
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(3))
+	assert.Nil(t, err)
+
+	// Read past the end of our data and verify our error describes it.
+	_, err = tp.GetByte()
+	assert.Nil(t, err)
+	_, err = tp.GetUint32()
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrEndOfStream))
+	assert.False(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+	var endErr *go_fuzz_utils.EndOfStreamError
+	if assert.True(t, errors.As(err, &endErr)) {
+		assert.EqualValues(t, go_fuzz_utils.EndOfStreamError{Requested: 4, Position: 1, End: 3, Length: 3}, *endErr)
+	}
+
+	// Seeded decisions need a seed, which should produce the same error.
+	err = tp.SetParamsDecisionMode(go_fuzz_utils.DecisionModeSeeded)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrEndOfStream))
+}
+
+func TestInvalidParamErrors(t *testing.T) {
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x10))
+	assert.Nil(t, err)
+
+	// Provide invalid parameters and verify each error matches our sentinel.
+	errs := []error{
+		tp.SetParamsStringBounds(-1, 0),
+		tp.SetParamsSliceBounds(2, 1),
+		tp.SetParamsMapBounds(0, -1),
+		tp.SetParamsBiases(0, 0, 2, 0),
+		tp.SetParamsBiasesCommon(0, -1),
+		tp.SetParamsDepthLimit(-1),
+		tp.SetParamsDecisionMode(go_fuzz_utils.DecisionMode(-1)),
+		tp.SetParamsExhaustionPolicy(go_fuzz_utils.ExhaustionPolicy(-1)),
+		tp.RegisterImplementations(reflect.TypeOf(0)),
+		tp.RegisterFiller(nil, nil),
+	}
+	_, readErr := tp.GetNBytes(-1)
+	errs = append(errs, readErr)
+	for _, err := range errs {
+		assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam), "unexpected error: %v", err)
+		assert.False(t, errors.Is(err, go_fuzz_utils.ErrEndOfStream))
+	}
+
+	// Verify the parameter is described.
+	var paramErr *go_fuzz_utils.InvalidParamError
+	if assert.True(t, errors.As(tp.SetParamsDepthLimit(-1), &paramErr)) {
+		assert.EqualValues(t, "depth limit", paramErr.Param)
+	}
+}
+
+func TestFillErrorPaths(t *testing.T) {
+	type item struct {
+		X uint16
+	}
+	type pathStruct struct {
+		A     uint8
+		Items []item
+		M     map[uint8]uint16
+	}
+
+	// Create our type provider with enough data to fill the first item, but only half of the second.
+	tp, err := go_fuzz_utils.NewTypeProvider([]byte{1, 2, 3, 4})
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsBiasesCommon(0, 0))
+	assert.Nil(t, tp.SetParamsSliceBounds(2, 2))
+
+	// Fill our structure and verify the error describes where it occurred.
+	var st pathStruct
+	err = tp.Fill(&st)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrEndOfStream))
+	var fillErr *go_fuzz_utils.FillError
+	if assert.True(t, errors.As(err, &fillErr)) {
+		assert.EqualValues(t, ".Items[1].X", fillErr.Path)
+	}
+
+	// Fill a map value which runs out of data.
+	tp, err = go_fuzz_utils.NewTypeProvider([]byte{1, 2})
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsBiasesCommon(0, 0))
+	assert.Nil(t, tp.SetParamsMapBounds(1, 1))
+	var m map[uint8]uint16
+	err = tp.Fill(&m)
+	if assert.True(t, errors.As(err, &fillErr)) {
+		assert.EqualValues(t, "[value 0]", fillErr.Path)
+	}
+
+	// Fill a nested structure with an invalid tag.
+	var tagged struct {
+		Inner [2]struct {
+			X int `fuzz:"min=x"`
+		}
+	}
+	err = tp.Fill(&tagged)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+	if assert.True(t, errors.As(err, &fillErr)) {
+		assert.EqualValues(t, ".Inner[0]", fillErr.Path)
+	}
+}
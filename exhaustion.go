@@ -0,0 +1,60 @@
+package go_fuzz_utils
+
+import "fmt"
+
+// ExhaustionPolicy describes how a TypeProvider behaves when a read goes past the end of its data.
+type ExhaustionPolicy int
+
+const (
+	// ExhaustionPolicyError indicates reads past the end of the data return an error. This is the default policy.
+	ExhaustionPolicyError ExhaustionPolicy = iota
+	// ExhaustionPolicyZero indicates reads past the end of the data return zero values, or empty slices and strings,
+	// so that short inputs can still be used to fill values. The TypeProvider records that this happened, which can
+	// be queried through Exhausted.
+	ExhaustionPolicyZero
+)
+
+// String obtains a human-readable name for the ExhaustionPolicy.
+func (p ExhaustionPolicy) String() string {
+	switch p {
+	case ExhaustionPolicyError:
+		return "error"
+	case ExhaustionPolicyZero:
+		return "zero"
+	default:
+		return fmt.Sprintf("ExhaustionPolicy(%d)", int(p))
+	}
+}
+
+// Exhausted indicates whether a read went past the end of the data since the TypeProvider was created or last reset,
+// producing zero values under ExhaustionPolicyZero.
+func (t *TypeProvider) Exhausted() bool {
+	return t.exhausted
+}
+
+// exhaust checks whether a read of the provided number of bytes would go past the end of the data while using
+// ExhaustionPolicyZero. If so, the remaining data is consumed and the TypeProvider is marked as exhausted.
+// Returns a boolean indicating whether the read should produce a zero value.
+func (t *TypeProvider) exhaust(expectedCount int) bool {
+	// If we return errors for reads past the end, or this read is valid, there is nothing to do.
+	if t.exhaustionPolicy != ExhaustionPolicyZero || expectedCount < 0 || t.end-t.position >= expectedCount {
+		return false
+	}
+
+	// Consume all remaining data and mark ourselves as exhausted.
+	t.position = t.end
+	t.exhausted = true
+	return true
+}
+
+// getFixedBytes obtains the requested number of bytes from the current position in the buffer to back a fixed-width
+// value. Unlike GetNBytes, reads past the end of the data under ExhaustionPolicyZero produce zeroed bytes of the
+// requested length.
+// Returns the requested bytes, or an error if the end of stream has been reached.
+func (t *TypeProvider) getFixedBytes(length int) ([]byte, error) {
+	// If we're exhausted, return zeroed bytes.
+	if t.exhaust(length) {
+		return make([]byte, length), nil
+	}
+	return t.GetNBytes(length)
+}
@@ -0,0 +1,339 @@
+package go_fuzz_utils
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// fieldTagName describes the struct tag key used to provide per-field fill constraints.
+const fieldTagName = "fuzz"
+
+// fieldConstraints describes fill constraints for a single struct field, parsed from its `fuzz` struct tag. Constraints
+// override the TypeProvider's parameters for the tagged field only, not for any values nested within it.
+type fieldConstraints struct {
+	// never indicates the field should never be filled.
+	never bool
+
+	// hasMin and hasMax indicate whether a minimum or maximum was provided for the field.
+	hasMin bool
+	hasMax bool
+	// minLength and maxLength describe the length bounds for string, slice and map fields, or the capacity bounds for
+	// channel fields.
+	minLength int
+	maxLength int
+	// minInt and maxInt describe the value range for signed integer fields.
+	minInt int64
+	maxInt int64
+	// minUint and maxUint describe the value range for unsigned integer fields.
+	minUint uint64
+	maxUint uint64
+	// minFloat and maxFloat describe the value range for float fields.
+	minFloat float64
+	maxFloat float64
+
+	// pattern describes the syntax tree of a regular expression which string fields are generated from.
+	pattern *syntax.Regexp
+
+	// hasNilBias indicates whether nilBias overrides the TypeProvider's nil bias for the field.
+	hasNilBias bool
+	// nilBias describes the probability of the field being set as nil (represented as a float between 0 and 1)
+	nilBias float32
+
+	// hasSkipBias indicates whether skipBias overrides the TypeProvider's skip bias for the field.
+	hasSkipBias bool
+	// skipBias describes the probability of the field being skipped (represented as a float between 0 and 1)
+	skipBias float32
+}
+
+// parseFieldTag parses a `fuzz` struct tag for a field of the provided type. Tags are a comma-separated list of
+// options: "-" (never fill the field), "min=<n>" and "max=<n>" (length bounds for strings, slices and maps, capacity
+// bounds for channels, or value ranges for numeric types), "nilbias=<p>" (nil probability for slices, maps, channels,
+// functions, pointers and interfaces), "skipbias=<p>" (skip probability) and "regex=<pattern>" (a pattern strings are
+// generated from, which must be the last option as it may contain commas). Constraints on a pointer field apply to the
+// value it points to, except for the nil bias.
+// Returns the parsed constraints, or an error if the tag is invalid for the provided type.
+func parseFieldTag(tag string, typ reflect.Type) (*fieldConstraints, error) {
+	// A lone dash indicates the field should never be filled.
+	c := &fieldConstraints{}
+	if tag == "-" {
+		c.never = true
+		return c, nil
+	}
+
+	// Determine the kind our min/max constraints apply to, looking through pointers.
+	valueType := typ
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+
+	// Parse each option in the tag.
+	for rest := tag; rest != ""; {
+		// Patterns may contain commas, so a pattern must be the last option and extends to the end of the tag.
+		option := strings.TrimLeftFunc(rest, unicode.IsSpace)
+		if strings.HasPrefix(option, "regex=") {
+			rest = ""
+		} else {
+			option, rest, _ = strings.Cut(rest, ",")
+			option = strings.TrimSpace(option)
+		}
+		if option == "" {
+			continue
+		}
+		key, value, found := strings.Cut(option, "=")
+		if !found {
+			return nil, fmt.Errorf("option %q is missing a value", option)
+		}
+
+		var err error
+		switch key {
+		case "min":
+			c.hasMin = true
+			err = c.parseBound(value, valueType, &c.minLength, &c.minInt, &c.minUint, &c.minFloat)
+		case "max":
+			c.hasMax = true
+			err = c.parseBound(value, valueType, &c.maxLength, &c.maxInt, &c.maxUint, &c.maxFloat)
+		case "nilbias":
+			if typ.Kind() != reflect.Slice && typ.Kind() != reflect.Map && typ.Kind() != reflect.Chan &&
+				typ.Kind() != reflect.Func && typ.Kind() != reflect.Ptr && typ.Kind() != reflect.Interface {
+				return nil, fmt.Errorf("option %q is not supported for kind %v", key, typ.Kind())
+			}
+			c.hasNilBias = true
+			c.nilBias, err = parseBias(value)
+		case "skipbias":
+			c.hasSkipBias = true
+			c.skipBias, err = parseBias(value)
+		case "regex":
+			if valueType.Kind() != reflect.String {
+				return nil, fmt.Errorf("option %q is not supported for kind %v", key, valueType.Kind())
+			}
+			c.pattern, err = parsePattern(value)
+		default:
+			return nil, fmt.Errorf("unknown option %q", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for option %q: %v", key, err)
+		}
+	}
+
+	// Patterns determine the length of strings themselves.
+	if c.pattern != nil && (c.hasMin || c.hasMax) {
+		return nil, fmt.Errorf("option \"regex\" cannot be combined with bounds")
+	}
+
+	// If only one of our bounds was provided, the other is derived from the type's limits.
+	if c.hasMin || c.hasMax {
+		minInt, maxInt, maxUint, maxFloat := typeLimits(valueType)
+		if !c.hasMax {
+			c.maxLength, c.maxInt, c.maxUint, c.maxFloat = math.MaxInt, maxInt, maxUint, maxFloat
+		}
+		if !c.hasMin {
+			c.minLength, c.minInt, c.minUint, c.minFloat = 0, minInt, 0, -maxFloat
+		}
+
+		// Validate our bounds are ordered correctly.
+		if c.maxLength < c.minLength || c.maxInt < c.minInt || c.maxUint < c.minUint || c.maxFloat < c.minFloat {
+			return nil, fmt.Errorf("minimum is larger than maximum")
+		}
+	}
+	return c, nil
+}
+
+// newInvalidTagError creates an error describing an invalid `fuzz` struct tag on the provided field of a struct type.
+// Returns the created error.
+func newInvalidTagError(field reflect.StructField, structType reflect.Type, err error) error {
+	return &InvalidParamError{
+		Param:  fmt.Sprintf("%s tag on field %s of %v", fieldTagName, field.Name, structType),
+		Reason: err.Error(),
+	}
+}
+
+// parseBound parses a min/max bound for a field of the provided type, storing it in the destination that applies to
+// the type's kind.
+// Returns an error if the bound could not be parsed or is not supported for the type.
+func (c *fieldConstraints) parseBound(value string, typ reflect.Type, length *int, i *int64, u *uint64, f *float64) error {
+	var err error
+	switch typ.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Chan:
+		*length, err = strconv.Atoi(value)
+		if err == nil && *length < 0 {
+			err = fmt.Errorf("length cannot be negative")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		*i, err = strconv.ParseInt(value, 0, typ.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		*u, err = strconv.ParseUint(value, 0, typ.Bits())
+	case reflect.Float32, reflect.Float64:
+		*f, err = strconv.ParseFloat(value, typ.Bits())
+		if err == nil && (math.IsNaN(*f) || math.IsInf(*f, 0)) {
+			err = fmt.Errorf("bound must be finite")
+		}
+	default:
+		err = fmt.Errorf("bounds are not supported for kind %v", typ.Kind())
+	}
+	return err
+}
+
+// typeLimits obtains the limits of the value range of the provided numeric type.
+// Returns the minimum and maximum signed integer, the maximum unsigned integer and the maximum float values.
+func typeLimits(typ reflect.Type) (int64, int64, uint64, float64) {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return -1 << (typ.Bits() - 1), 1<<(typ.Bits()-1) - 1, 0, 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return 0, 0, math.MaxUint64 >> (64 - typ.Bits()), 0
+	case reflect.Float32:
+		return 0, 0, 0, math.MaxFloat32
+	default:
+		// Other types have no meaningful limits, so we return the widest ones.
+		return math.MinInt64, math.MaxInt64, math.MaxUint64, math.MaxFloat64
+	}
+}
+
+// parseBias parses a probability represented as a float between 0 and 1.
+// Returns the parsed probability, or an error if it could not be parsed or was not within the [0,1] range.
+func parseBias(value string) (float32, error) {
+	bias, err := strconv.ParseFloat(value, 32)
+	if err != nil {
+		return 0, err
+	}
+	if bias < 0 || bias > 1 {
+		return 0, fmt.Errorf("bias must be between [0,1]")
+	}
+	return float32(bias), nil
+}
+
+// elemConstraints obtains the constraints which apply to the value a pointer field points to.
+func (c *fieldConstraints) elemConstraints() *fieldConstraints {
+	if c == nil {
+		return nil
+	}
+	elem := *c
+	elem.hasNilBias = false
+	elem.hasSkipBias = false
+	return &elem
+}
+
+// getNilBias obtains the nil bias for the field, or the provided default if the field does not override it.
+func (c *fieldConstraints) getNilBias(defaultBias float32) float32 {
+	if c == nil || !c.hasNilBias {
+		return defaultBias
+	}
+	return c.nilBias
+}
+
+// getSkipBias obtains the skip bias for the field, or the provided default if the field does not override it.
+func (c *fieldConstraints) getSkipBias(defaultBias float32) float32 {
+	if c == nil || !c.hasSkipBias {
+		return defaultBias
+	}
+	return c.skipBias
+}
+
+// hasValueConstraints determines whether the field has bounds or a pattern constraining its value.
+func (c *fieldConstraints) hasValueConstraints() bool {
+	return c != nil && (c.hasMin || c.hasMax || c.pattern != nil)
+}
+
+// getPattern obtains the syntax tree of the pattern string fields are generated from, or nil if the field has none.
+func (c *fieldConstraints) getPattern() *syntax.Regexp {
+	if c == nil {
+		return nil
+	}
+	return c.pattern
+}
+
+// getLengthBounds obtains the length bounds for the field, or the provided defaults if the field does not override
+// them. If only one bound is overridden, the other default is adjusted so the bounds remain ordered.
+func (c *fieldConstraints) getLengthBounds(defaultMin int, defaultMax int) (int, int) {
+	if c == nil || (!c.hasMin && !c.hasMax) {
+		return defaultMin, defaultMax
+	}
+	minLength, maxLength := defaultMin, defaultMax
+	if c.hasMin {
+		minLength = c.minLength
+		if maxLength < minLength {
+			maxLength = minLength
+		}
+	}
+	if c.hasMax {
+		maxLength = c.maxLength
+		if maxLength < minLength {
+			minLength = maxLength
+		}
+	}
+	return minLength, maxLength
+}
+
+// hasIntegerRange indicates whether the field has a value range constraint for the provided integer value, in which
+// case the value is read directly within its range rather than populated based on its kind.
+func (c *fieldConstraints) hasIntegerRange(v reflect.Value) bool {
+	return c != nil && (c.hasMin || c.hasMax) && (v.CanInt() || v.CanUint())
+}
+
+// fillIntegerRange populates an integer value within the range described by the provided constraints, using the
+// bounded integer getters.
+// Returns an error if one is encountered.
+func (t *TypeProvider) fillIntegerRange(v reflect.Value, c *fieldConstraints) error {
+	if v.CanInt() {
+		x, err := t.GetInt64InRange(c.minInt, c.maxInt)
+		if err != nil {
+			return err
+		}
+		v.SetInt(x)
+	} else {
+		x, err := t.GetUint64InRange(c.minUint, c.maxUint)
+		if err != nil {
+			return err
+		}
+		v.SetUint(x)
+	}
+	return nil
+}
+
+// constrainValue maps a filled float value into the range described by the field's constraints. Values already in
+// range are left unchanged. Integer values are instead read within their range by fillIntegerRange.
+func (c *fieldConstraints) constrainValue(v reflect.Value) {
+	// If we have no range, there is nothing to do.
+	if c == nil || (!c.hasMin && !c.hasMax) {
+		return
+	}
+
+	// Map our value into our range.
+	if v.CanFloat() {
+		x := v.Float()
+		if math.IsNaN(x) || x < c.minFloat || x > c.maxFloat {
+			// Derive a fraction of our range from the mantissa bits of the value.
+			fraction := float64(math.Float64bits(x)&(1<<52-1)) / (1 << 52)
+			v.SetFloat(c.minFloat*(1-fraction) + c.maxFloat*fraction)
+		}
+	}
+}
+
+// encodeValue verifies a numeric value is within the range described by the field's constraints, as values outside of
+// it cannot be produced when filling the field.
+// Returns an error if the value is not within the range.
+func (c *fieldConstraints) encodeValue(v reflect.Value) error {
+	// If we have no range, there is nothing to do.
+	if c == nil || (!c.hasMin && !c.hasMax) {
+		return nil
+	}
+
+	// Verify our value is within our range depending on its kind.
+	inRange := true
+	if v.CanInt() {
+		inRange = v.Int() >= c.minInt && v.Int() <= c.maxInt
+	} else if v.CanUint() {
+		inRange = v.Uint() >= c.minUint && v.Uint() <= c.maxUint
+	} else if v.CanFloat() {
+		inRange = v.Float() >= c.minFloat && v.Float() <= c.maxFloat
+	}
+	if !inRange {
+		return fmt.Errorf("value %v is not within the range of its field constraints", v)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package go_fuzz_utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+type taggedStruct struct {
+	Name     string            `fuzz:"min=1,max=4"`
+	Tags     []string          `fuzz:"max=3,nilbias=0"`
+	Photo    []byte            `fuzz:"min=2,max=2"`
+	Labels   map[int]int       `fuzz:"max=2,nilbias=1"`
+	Nickname *string           `fuzz:"nilbias=0,min=5,max=5"`
+	Age      uint8             `fuzz:"min=18,max=99"`
+	Offset   int16             `fuzz:"min=-3,max=3"`
+	Ratio    float64           `fuzz:"min=0,max=1"`
+	Big      int64             `fuzz:"min=-5"`
+	Ignored  string            `fuzz:"-"`
+	Skipped  int32             `fuzz:"skipbias=1"`
+	Nested   map[string]string `fuzz:"min=1,max=1,nilbias=0"`
+}
+
+func TestFillFieldTags(t *testing.T) {
+	// Create our fuzz data
+	b := generateTestData(0x10000)
+
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider(b)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsBiasesCommon(0, 0))
+
+	// Fill our structure a number of times, verifying our constraints are always upheld.
+	for i := 0; i < 50; i++ {
+		st := taggedStruct{Ignored: "ignored"}
+		err = tp.Fill(&st)
+		assert.Nil(t, err)
+
+		assert.GreaterOrEqual(t, len(st.Name), 1)
+		assert.LessOrEqual(t, len(st.Name), 4)
+		assert.NotNil(t, st.Tags)
+		assert.LessOrEqual(t, len(st.Tags), 3)
+		assert.EqualValues(t, 2, len(st.Photo))
+		assert.Nil(t, st.Labels)
+		if assert.NotNil(t, st.Nickname) {
+			assert.EqualValues(t, 5, len(*st.Nickname))
+		}
+		assert.GreaterOrEqual(t, st.Age, uint8(18))
+		assert.LessOrEqual(t, st.Age, uint8(99))
+		assert.GreaterOrEqual(t, st.Offset, int16(-3))
+		assert.LessOrEqual(t, st.Offset, int16(3))
+		assert.GreaterOrEqual(t, st.Ratio, 0.0)
+		assert.LessOrEqual(t, st.Ratio, 1.0)
+		assert.GreaterOrEqual(t, st.Big, int64(-5))
+		assert.EqualValues(t, "ignored", st.Ignored)
+		assert.EqualValues(t, 0, st.Skipped)
+		assert.EqualValues(t, 1, len(st.Nested))
+
+		// Constraints should only apply to the tagged field, so strings within our slice use the global bounds.
+		for _, tag := range st.Tags {
+			assert.LessOrEqual(t, len(tag), 15)
+		}
+	}
+}
+
+func TestFillInvalidFieldTags(t *testing.T) {
+	// Create our fuzz data
+	b := generateTestData(0x1000)
+
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider(b)
+	assert.Nil(t, err)
+
+	// Define a number of structures with invalid tags, each of which should produce an error.
+	var unknownOption struct {
+		X int `fuzz:"foo=1"`
+	}
+	assert.NotNil(t, tp.Fill(&unknownOption))
+
+	var missingValue struct {
+		X int `fuzz:"min"`
+	}
+	assert.NotNil(t, tp.Fill(&missingValue))
+
+	var unorderedBounds struct {
+		X string `fuzz:"min=5,max=2"`
+	}
+	assert.NotNil(t, tp.Fill(&unorderedBounds))
+
+	var outOfRangeBound struct {
+		X int8 `fuzz:"max=300"`
+	}
+	assert.NotNil(t, tp.Fill(&outOfRangeBound))
+
+	var invalidBias struct {
+		X []int `fuzz:"nilbias=2"`
+	}
+	assert.NotNil(t, tp.Fill(&invalidBias))
+
+	var unsupportedNilBias struct {
+		X int `fuzz:"nilbias=0.5"`
+	}
+	assert.NotNil(t, tp.Fill(&unsupportedNilBias))
+
+	var unsupportedBounds struct {
+		X [4]int `fuzz:"min=1"`
+	}
+	assert.NotNil(t, tp.Fill(&unsupportedBounds))
+}
@@ -0,0 +1,308 @@
+package go_fuzz_utils
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// FloatMode describes how Fill produces float and complex values.
+type FloatMode int
+
+const (
+	// FloatModeRaw indicates floats are produced by reinterpreting raw bits, as done by GetFloat32 and GetFloat64.
+	// Every float value can be produced, including NaN and infinities. This is the default mode.
+	FloatModeRaw FloatMode = iota
+	// FloatModeFinite indicates floats are produced by GetFiniteFloat32 and GetFiniteFloat64, which never produce NaN or
+	// infinities.
+	FloatModeFinite
+	// FloatModeUnit indicates floats are produced by GetUnitFloat32 and GetUnitFloat64, within the range [0, 1).
+	FloatModeUnit
+)
+
+// String obtains a human-readable name for the FloatMode.
+func (m FloatMode) String() string {
+	switch m {
+	case FloatModeRaw:
+		return "raw"
+	case FloatModeFinite:
+		return "finite"
+	case FloatModeUnit:
+		return "unit"
+	default:
+		return fmt.Sprintf("FloatMode(%d)", int(m))
+	}
+}
+
+// specialFloats describes the special float values which can be produced with a special value probability.
+var specialFloats = []float64{math.NaN(), math.Inf(1), math.Inf(-1)}
+
+// indexOfSpecialFloat obtains the index of the provided value in specialFloats.
+// Returns the index of the value, or -1 if it is not a special value.
+func indexOfSpecialFloat(f float64) int {
+	if math.IsNaN(f) {
+		return 0
+	} else if math.IsInf(f, 1) {
+		return 1
+	} else if math.IsInf(f, -1) {
+		return 2
+	}
+	return -1
+}
+
+// GetParamsFloatMode obtains the mode used to produce float and complex values when using Fill.
+func (t *TypeProvider) GetParamsFloatMode() FloatMode {
+	return t.floatMode
+}
+
+// SetParamsFloatMode sets the mode used to produce float and complex values when using Fill. Fields with a range
+// constraint are mapped into their range after being produced.
+// Returns an error if the float mode is invalid.
+func (t *TypeProvider) SetParamsFloatMode(mode FloatMode) error {
+	// Validate our parameters and set them accordingly
+	if mode != FloatModeRaw && mode != FloatModeFinite && mode != FloatModeUnit {
+		return &InvalidParamError{Param: "float mode", Reason: mode.String()}
+	}
+	t.floatMode = mode
+	return nil
+}
+
+// GetParamsFloatSpecialBias obtains the probability of Fill producing NaN or an infinity for float and complex values
+// (represented as a float between 0 and 1).
+func (t *TypeProvider) GetParamsFloatSpecialBias() float32 {
+	return t.floatSpecialBias
+}
+
+// SetParamsFloatSpecialBias sets the probability of Fill producing NaN or an infinity for float and complex values
+// (represented as a float between 0 and 1), regardless of the float mode. The bias defaults to zero, in which case no
+// decisions are made.
+// Returns an error if the bias is not within the [0,1] range.
+func (t *TypeProvider) SetParamsFloatSpecialBias(bias float32) error {
+	// Validate our parameters and set them accordingly
+	if bias < 0 || bias > 1 {
+		return &InvalidParamError{Param: "bias", Reason: "bias must be between [0,1]"}
+	}
+	t.floatSpecialBias = bias
+	return nil
+}
+
+// GetFiniteFloat32 obtains a finite float32 from the current position in the buffer. Bits which would represent NaN
+// or an infinity have the most significant bit of their exponent cleared, so finite values are read unchanged.
+// This advances the position by 4.
+// Returns the read float32, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetFiniteFloat32() (float32, error) {
+	// Obtain our bits, making them finite if their exponent is all ones.
+	x, err := t.readUint32()
+	if x&0x7F800000 == 0x7F800000 {
+		x ^= 1 << 30
+	}
+	return math.Float32frombits(x), err
+}
+
+// GetFiniteFloat64 obtains a finite float64 from the current position in the buffer. Bits which would represent NaN
+// or an infinity have the most significant bit of their exponent cleared, so finite values are read unchanged.
+// This advances the position by 8.
+// Returns the read float64, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetFiniteFloat64() (float64, error) {
+	// Obtain our bits, making them finite if their exponent is all ones.
+	x, err := t.readUint64()
+	if x&0x7FF0000000000000 == 0x7FF0000000000000 {
+		x ^= 1 << 62
+	}
+	return math.Float64frombits(x), err
+}
+
+// GetUnitFloat32 obtains a float32 within the range [0, 1) from the current position in the buffer. Values are
+// uniformly distributed multiples of 2^-24.
+// This advances the position by 4.
+// Returns the read float32, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetUnitFloat32() (float32, error) {
+	// Use the upper 24 bits of our value as the numerator of our fraction.
+	x, err := t.readUint32()
+	return float32(x>>8) / (1 << 24), err
+}
+
+// GetUnitFloat64 obtains a float64 within the range [0, 1) from the current position in the buffer. Values are
+// uniformly distributed multiples of 2^-53.
+// This advances the position by 8.
+// Returns the read float64, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetUnitFloat64() (float64, error) {
+	// Use the upper 53 bits of our value as the numerator of our fraction.
+	x, err := t.readUint64()
+	return float64(x>>11) / (1 << 53), err
+}
+
+// GetFloat32InRange obtains a float32 within the inclusive range [min, max] from the current position in the buffer,
+// by interpolating between the bounds with a value obtained as done by GetUnitFloat32.
+// This advances the position by 4.
+// Returns the read float32, or an error if the range is invalid or the end of stream has been reached.
+func (t *TypeProvider) GetFloat32InRange(min float32, max float32) (float32, error) {
+	// Validate our parameters
+	if err := validateFloatRange(float64(min), float64(max)); err != nil {
+		return 0, err
+	}
+
+	// Obtain our fraction of the range and interpolate between our bounds.
+	u, err := t.GetUnitFloat32()
+	if err != nil {
+		return 0, err
+	}
+	return float32(clampFloat(float64(min)*(1-float64(u))+float64(max)*float64(u), float64(min), float64(max))), nil
+}
+
+// GetFloat64InRange obtains a float64 within the inclusive range [min, max] from the current position in the buffer,
+// by interpolating between the bounds with a value obtained as done by GetUnitFloat64.
+// This advances the position by 8.
+// Returns the read float64, or an error if the range is invalid or the end of stream has been reached.
+func (t *TypeProvider) GetFloat64InRange(min float64, max float64) (float64, error) {
+	// Validate our parameters
+	if err := validateFloatRange(min, max); err != nil {
+		return 0, err
+	}
+
+	// Obtain our fraction of the range and interpolate between our bounds. This does not overflow, even if the
+	// distance between our bounds is larger than the maximum float.
+	u, err := t.GetUnitFloat64()
+	if err != nil {
+		return 0, err
+	}
+	return clampFloat(min*(1-u)+max*u, min, max), nil
+}
+
+// validateFloatRange verifies the provided bounds describe a valid range of finite floats.
+// Returns an error if the range is invalid.
+func validateFloatRange(min float64, max float64) error {
+	if math.IsNaN(min) || math.IsNaN(max) || math.IsInf(min, 0) || math.IsInf(max, 0) || max < min {
+		return &InvalidParamError{Param: "range", Reason: fmt.Sprintf("min: %v, max: %v", min, max)}
+	}
+	return nil
+}
+
+// clampFloat limits the provided value to the range [min, max], correcting any rounding errors in an interpolation.
+func clampFloat(x float64, min float64, max float64) float64 {
+	return math.Max(min, math.Min(max, x))
+}
+
+// GetFloat32WithSpecial obtains a float32 which is NaN, positive infinity or negative infinity with the provided
+// probability, and a finite value obtained by GetFiniteFloat32 otherwise. Deciding whether a special value is produced,
+// and which one, uses the TypeProvider's decision mode.
+// Returns the obtained float32, or an error if the probability is invalid or the end of stream has been reached.
+func (t *TypeProvider) GetFloat32WithSpecial(probability float32) (float32, error) {
+	// Validate our parameters
+	if probability < 0 || probability > 1 {
+		return 0, &InvalidParamError{Param: "probability", Reason: "probability must be between [0,1]"}
+	}
+
+	// Determine if we should produce a special value, otherwise obtain a finite one.
+	if f, ok := t.getSpecialFloat(probability); ok {
+		return float32(f), nil
+	}
+	return t.GetFiniteFloat32()
+}
+
+// GetFloat64WithSpecial obtains a float64 which is NaN, positive infinity or negative infinity with the provided
+// probability, and a finite value obtained by GetFiniteFloat64 otherwise. Deciding whether a special value is produced,
+// and which one, uses the TypeProvider's decision mode.
+// Returns the obtained float64, or an error if the probability is invalid or the end of stream has been reached.
+func (t *TypeProvider) GetFloat64WithSpecial(probability float32) (float64, error) {
+	// Validate our parameters
+	if probability < 0 || probability > 1 {
+		return 0, &InvalidParamError{Param: "probability", Reason: "probability must be between [0,1]"}
+	}
+
+	// Determine if we should produce a special value, otherwise obtain a finite one.
+	if f, ok := t.getSpecialFloat(probability); ok {
+		return f, nil
+	}
+	return t.GetFiniteFloat64()
+}
+
+// getSpecialFloat determines whether a special float value should be produced given the provided probability, and
+// selects one if so. No decisions are made if the probability is zero.
+// Returns the special value, and a boolean indicating whether one should be produced.
+func (t *TypeProvider) getSpecialFloat(probability float32) (float64, bool) {
+	if probability <= 0 || !t.getRandomBool(probability) {
+		return 0, false
+	}
+	return specialFloats[t.getRandomSize(0, len(specialFloats)-1)], true
+}
+
+// fillInterestingFloats obtains the interesting values of the provided float kind which Fill can produce in the float
+// mode: all of them for FloatModeRaw, the finite ones for FloatModeFinite, and those within [0, 1) for FloatModeUnit.
+// Returns the bits of the interesting values which can be produced.
+func (t *TypeProvider) fillInterestingFloats(kind reflect.Kind) []uint64 {
+	// If every value can be produced, use the whole table.
+	table := t.interestingValues[kind]
+	if t.floatMode == FloatModeRaw {
+		return table
+	}
+
+	// Otherwise collect the values which can be produced in our float mode.
+	var values []uint64
+	for _, bits := range table {
+		f := math.Float64frombits(bits)
+		if kind == reflect.Float32 {
+			f = float64(math.Float32frombits(uint32(bits)))
+		}
+		if t.floatMode == FloatModeFinite && !math.IsNaN(f) && !math.IsInf(f, 0) {
+			values = append(values, bits)
+		} else if t.floatMode == FloatModeUnit && f >= 0 && f < 1 && !math.Signbit(f) {
+			values = append(values, bits)
+		}
+	}
+	return values
+}
+
+// getFillFloat32 obtains a float32 to populate a value with in Fill, given the float mode and special value bias.
+// Returns the obtained float32, or an error if the end of stream has been reached.
+func (t *TypeProvider) getFillFloat32() (float32, error) {
+	// Determine if we should produce a special value.
+	if f, ok := t.getSpecialFloat(t.floatSpecialBias); ok {
+		return float32(f), nil
+	}
+
+	// Determine if we should produce an interesting value which can be produced in our float mode. Raw values make
+	// this decision in GetFloat32.
+	if t.floatMode != FloatModeRaw {
+		if x, ok := t.selectInterestingValue(t.fillInterestingFloats(reflect.Float32)); ok {
+			return math.Float32frombits(uint32(x)), nil
+		}
+	}
+
+	// Obtain our value according to our float mode.
+	switch t.floatMode {
+	case FloatModeFinite:
+		return t.GetFiniteFloat32()
+	case FloatModeUnit:
+		return t.GetUnitFloat32()
+	default:
+		return t.GetFloat32()
+	}
+}
+
+// getFillFloat64 obtains a float64 to populate a value with in Fill, given the float mode and special value bias.
+// Returns the obtained float64, or an error if the end of stream has been reached.
+func (t *TypeProvider) getFillFloat64() (float64, error) {
+	// Determine if we should produce a special value.
+	if f, ok := t.getSpecialFloat(t.floatSpecialBias); ok {
+		return f, nil
+	}
+
+	// Determine if we should produce an interesting value which can be produced in our float mode. Raw values make
+	// this decision in GetFloat64.
+	if t.floatMode != FloatModeRaw {
+		if x, ok := t.selectInterestingValue(t.fillInterestingFloats(reflect.Float64)); ok {
+			return math.Float64frombits(x), nil
+		}
+	}
+
+	// Obtain our value according to our float mode.
+	switch t.floatMode {
+	case FloatModeFinite:
+		return t.GetFiniteFloat64()
+	case FloatModeUnit:
+		return t.GetUnitFloat64()
+	default:
+		return t.GetFloat64()
+	}
+}
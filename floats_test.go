@@ -0,0 +1,191 @@
+package go_fuzz_utils_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+func TestFloatGetters(t *testing.T) {
+	// Create our type provider with bits representing NaN and an infinity.
+	tp, err := go_fuzz_utils.NewTypeProvider([]byte{
+		0x7F, 0xF8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, // NaN as a float64
+		0xFF, 0x80, 0x00, 0x00, // -Inf as a float32
+		0x3F, 0xF0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // 1.0 as a float64
+		0x80, 0x00, 0x00, 0x00, // half as a unit float32
+		0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, // the largest unit float64
+	})
+	assert.Nil(t, err)
+
+	// Non-finite bits are made finite, while finite bits are read unchanged.
+	f64, err := tp.GetFiniteFloat64()
+	assert.Nil(t, err)
+	assert.False(t, math.IsNaN(f64) || math.IsInf(f64, 0))
+	f32, err := tp.GetFiniteFloat32()
+	assert.Nil(t, err)
+	assert.False(t, math.IsNaN(float64(f32)) || math.IsInf(float64(f32), 0))
+	f64, err = tp.GetFiniteFloat64()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1.0, f64)
+
+	// Unit floats are within [0, 1).
+	f32, err = tp.GetUnitFloat32()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0.5, f32)
+	f64, err = tp.GetUnitFloat64()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1-math.Pow(2, -53), f64)
+
+	// Reads past the end of our data return an error.
+	_, err = tp.GetUnitFloat64()
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrEndOfStream))
+}
+
+func TestFloatRanges(t *testing.T) {
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+	assert.Nil(t, err)
+
+	// Obtain a number of values and verify they're always within their range, including ranges wider than the maximum
+	// float.
+	for i := 0; i < 50; i++ {
+		f64, err := tp.GetFloat64InRange(-2.5, 10)
+		assert.Nil(t, err)
+		assert.True(t, f64 >= -2.5 && f64 <= 10)
+		f64, err = tp.GetFloat64InRange(-math.MaxFloat64, math.MaxFloat64)
+		assert.Nil(t, err)
+		assert.False(t, math.IsInf(f64, 0) || math.IsNaN(f64))
+		f32, err := tp.GetFloat32InRange(100, 101)
+		assert.Nil(t, err)
+		assert.True(t, f32 >= 100 && f32 <= 101)
+	}
+
+	// Equal bounds produce the bound.
+	f64, err := tp.GetFloat64InRange(3, 3)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, f64)
+
+	// Invalid ranges return an error.
+	_, err = tp.GetFloat64InRange(1, 0)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+	_, err = tp.GetFloat64InRange(0, math.Inf(1))
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+	_, err = tp.GetFloat32InRange(float32(math.NaN()), 0)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+}
+
+func TestFloatSpecialValues(t *testing.T) {
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider([]byte{0x00, 0x00, 0x02, 0xFF, 0x3F, 0x80, 0x00, 0x00})
+	assert.Nil(t, err)
+
+	// With a full probability, a single byte selects the special value.
+	f64, err := tp.GetFloat64WithSpecial(1)
+	assert.Nil(t, err)
+	assert.True(t, math.IsNaN(f64))
+	f32, err := tp.GetFloat32WithSpecial(1)
+	assert.Nil(t, err)
+	assert.True(t, math.IsNaN(float64(f32)))
+	f64, err = tp.GetFloat64WithSpecial(1)
+	assert.Nil(t, err)
+	assert.True(t, math.IsInf(f64, -1))
+
+	// Otherwise, a decision byte determines whether a finite value is read instead.
+	f32, err = tp.GetFloat32WithSpecial(0.5)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, f32)
+
+	// Invalid probabilities return an error.
+	_, err = tp.GetFloat64WithSpecial(-1)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+}
+
+type floatStruct struct {
+	F32  float32
+	F64  float64
+	C64  complex64
+	C128 complex128
+}
+
+func TestFillFloatModes(t *testing.T) {
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+	assert.Nil(t, err)
+	assert.EqualValues(t, go_fuzz_utils.FloatModeRaw, tp.GetParamsFloatMode())
+
+	// Fill a number of values in each mode and verify they're in the range of the mode.
+	assert.Nil(t, tp.SetParamsFloatMode(go_fuzz_utils.FloatModeUnit))
+	assert.EqualValues(t, go_fuzz_utils.FloatModeUnit, tp.GetParamsFloatMode())
+	for i := 0; i < 20; i++ {
+		var v floatStruct
+		assert.Nil(t, tp.Fill(&v))
+		for _, f := range []float64{float64(v.F32), v.F64, float64(real(v.C64)), imag(v.C128)} {
+			assert.True(t, f >= 0 && f < 1)
+		}
+	}
+	assert.Nil(t, tp.SetParamsFloatMode(go_fuzz_utils.FloatModeFinite))
+	for i := 0; i < 20; i++ {
+		var v floatStruct
+		assert.Nil(t, tp.Fill(&v))
+		for _, f := range []float64{float64(v.F32), v.F64, float64(real(v.C64)), imag(v.C128)} {
+			assert.False(t, math.IsNaN(f) || math.IsInf(f, 0))
+		}
+	}
+
+	// With a full special bias, every value is special regardless of the mode.
+	assert.Nil(t, tp.SetParamsFloatSpecialBias(1))
+	assert.EqualValues(t, 1, tp.GetParamsFloatSpecialBias())
+	var v floatStruct
+	assert.Nil(t, tp.Fill(&v))
+	for _, f := range []float64{float64(v.F32), v.F64, float64(real(v.C64)), imag(v.C128)} {
+		assert.True(t, math.IsNaN(f) || math.IsInf(f, 0))
+	}
+
+	// Invalid parameters return an error.
+	assert.True(t, errors.Is(tp.SetParamsFloatMode(go_fuzz_utils.FloatMode(-1)), go_fuzz_utils.ErrInvalidParam))
+	assert.True(t, errors.Is(tp.SetParamsFloatSpecialBias(2), go_fuzz_utils.ErrInvalidParam))
+}
+
+func TestEncodeFloatModes(t *testing.T) {
+	modes := []go_fuzz_utils.FloatMode{go_fuzz_utils.FloatModeRaw, go_fuzz_utils.FloatModeFinite, go_fuzz_utils.FloatModeUnit}
+	for _, mode := range modes {
+		t.Run(mode.String(), func(t *testing.T) {
+			// Create a value which can be produced in every mode, along with special values.
+			value := floatStruct{F32: 0.25, F64: math.Inf(1), C64: complex(0.5, 0), C128: complex(math.Inf(-1), 0.125)}
+
+			// Encode our value and fill a new value from the encoded data.
+			tp, err := go_fuzz_utils.NewTypeProvider(nil)
+			assert.Nil(t, err)
+			assert.Nil(t, tp.SetParamsFloatMode(mode))
+			assert.Nil(t, tp.SetParamsFloatSpecialBias(0.5))
+			data, err := tp.Encode(&value)
+			assert.Nil(t, err)
+			tp, err = go_fuzz_utils.NewTypeProvider(data)
+			assert.Nil(t, err)
+			assert.Nil(t, tp.SetParamsFloatMode(mode))
+			assert.Nil(t, tp.SetParamsFloatSpecialBias(0.5))
+			var filled floatStruct
+			assert.Nil(t, tp.Fill(&filled))
+			assert.EqualValues(t, value, filled)
+
+			// Special values cannot be encoded without a special bias, unless floats are raw.
+			tp, err = go_fuzz_utils.NewTypeProvider(nil)
+			assert.Nil(t, err)
+			assert.Nil(t, tp.SetParamsFloatMode(mode))
+			_, err = tp.Encode(&value)
+			assert.Equal(t, mode != go_fuzz_utils.FloatModeRaw, err != nil)
+		})
+	}
+
+	// Values outside of the unit interval, or not a multiple of its precision, cannot be encoded in the unit mode.
+	tp, err := go_fuzz_utils.NewTypeProvider(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsFloatMode(go_fuzz_utils.FloatModeUnit))
+	for _, f := range []float64{1, -0.5, math.Copysign(0, -1), math.Pow(2, -60)} {
+		_, err = tp.Encode(&f)
+		assert.NotNil(t, err, "value %v should not be encodable", f)
+	}
+}
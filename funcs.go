@@ -0,0 +1,128 @@
+package go_fuzz_utils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// errorType describes the error interface, which synthesized functions return fuzzed errors for.
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// FuncCall describes a call made to a function synthesized by Fill for a func-typed value.
+type FuncCall struct {
+	// Type describes the type of the function which was called.
+	Type reflect.Type
+	// Args describes the arguments the function was called with. Variadic arguments are provided as a single slice.
+	Args []interface{}
+	// Results describes the results the function returned.
+	Results []interface{}
+	// Err describes an error encountered while filling the results, in which case the remaining results are zero
+	// values.
+	Err error
+}
+
+// GetParamsFuncNilBias obtains the probability of func-typed values being set as nil when using Fill (represented as
+// a float between 0 and 1).
+func (t *TypeProvider) GetParamsFuncNilBias() float32 {
+	return t.funcNilBias
+}
+
+// SetParamsFuncNilBias sets the probability of func-typed values being set as nil when using Fill (represented as a
+// float between 0 and 1). Other func-typed values are set to functions which fill their results from this
+// TypeProvider when called. The bias defaults to 0.05 and is also set by SetParamsBiasesCommon.
+// Returns an error if the bias is not within the [0,1] range.
+func (t *TypeProvider) SetParamsFuncNilBias(bias float32) error {
+	// Validate our parameters and set them accordingly
+	if bias < 0 || bias > 1 {
+		return &InvalidParamError{Param: "bias", Reason: "bias must be between [0,1]"}
+	}
+	t.funcNilBias = bias
+	return nil
+}
+
+// GetParamsFuncErrorBias obtains the probability of error results of synthesized functions being set to a non-nil
+// error (represented as a float between 0 and 1).
+func (t *TypeProvider) GetParamsFuncErrorBias() float32 {
+	return t.funcErrorBias
+}
+
+// SetParamsFuncErrorBias sets the probability of error results of synthesized functions being set to a non-nil error
+// (represented as a float between 0 and 1). Such errors are created with a message read from the data. This only
+// applies while no implementations of error are registered, otherwise error results are filled like other interfaces.
+// The bias defaults to 0.25.
+// Returns an error if the bias is not within the [0,1] range.
+func (t *TypeProvider) SetParamsFuncErrorBias(bias float32) error {
+	// Validate our parameters and set them accordingly
+	if bias < 0 || bias > 1 {
+		return &InvalidParamError{Param: "bias", Reason: "bias must be between [0,1]"}
+	}
+	t.funcErrorBias = bias
+	return nil
+}
+
+// GetFuncCalls obtains the calls made to functions synthesized by Fill since this TypeProvider was created, reset, or
+// had its calls cleared, in the order they were made.
+func (t *TypeProvider) GetFuncCalls() []FuncCall {
+	return t.funcCalls
+}
+
+// ClearFuncCalls clears the calls recorded for functions synthesized by Fill.
+func (t *TypeProvider) ClearFuncCalls() {
+	t.funcCalls = nil
+}
+
+// makeFunc creates a function of the provided type which, when called, records its call and fills its results from
+// this TypeProvider, at the provided depth. As with other reads, calls advance the position of this TypeProvider, so
+// synthesized functions must not be called concurrently with each other or other reads.
+// Returns the created function.
+func (t *TypeProvider) makeFunc(typ reflect.Type, currentDepth int) reflect.Value {
+	return reflect.MakeFunc(typ, func(args []reflect.Value) []reflect.Value {
+		// Record the arguments we were called with.
+		call := FuncCall{Type: typ, Args: make([]interface{}, len(args))}
+		for i, arg := range args {
+			call.Args[i] = arg.Interface()
+		}
+
+		// Fill each of our results, leaving the remaining results as zero values if we encounter an error.
+		results := make([]reflect.Value, typ.NumOut())
+		call.Results = make([]interface{}, len(results))
+		for i := range results {
+			results[i] = reflect.New(typ.Out(i)).Elem()
+			if call.Err == nil {
+				var err error
+				if results[i].Type() == errorType && len(t.implementations[errorType]) == 0 {
+					err = t.fillFuncError(results[i])
+				} else {
+					err = t.fillValue(results[i], currentDepth, nil)
+				}
+				if err != nil {
+					call.Err = wrapFillError(err, fmt.Sprintf("(result %d)", i))
+				}
+			}
+			call.Results[i] = results[i].Interface()
+		}
+
+		// Record our call and return our results.
+		t.funcCalls = append(t.funcCalls, call)
+		return results
+	})
+}
+
+// fillFuncError sets an error result of a synthesized function to nil, or to an error with a message read from the
+// data, based on the func error bias.
+// Returns an error if the end of stream has been reached.
+func (t *TypeProvider) fillFuncError(v reflect.Value) error {
+	// Determine if we should return an error.
+	if !t.getRandomBool(t.funcErrorBias) {
+		return nil
+	}
+
+	// Create our error with a message read from the data.
+	message, err := t.getString(t.stringMinLength, t.stringMaxLength)
+	if err != nil {
+		return err
+	}
+	v.Set(reflect.ValueOf(errors.New(message)))
+	return nil
+}
@@ -0,0 +1,139 @@
+package go_fuzz_utils_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+// callbacks describes a struct with func-typed fields.
+type callbacks struct {
+	Validate func(string, int) (bool, error)
+	OnEvent  func(...uint8)
+	Optional func() `fuzz:"nilbias=1"`
+}
+
+func TestFillFuncs(t *testing.T) {
+	// Create our type provider, with data for our nil decisions followed by data for our function results.
+	tp, err := go_fuzz_utils.NewTypeProvider([]byte{0xFF, 0xFF, 0x02})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0.05, tp.GetParamsFuncNilBias())
+
+	// Fill our struct. Our functions should be synthesized unless our tag makes them nil.
+	var c callbacks
+	assert.Nil(t, tp.Fill(&c))
+	assert.NotNil(t, c.Validate)
+	assert.NotNil(t, c.OnEvent)
+	assert.Nil(t, c.Optional)
+	assert.Empty(t, tp.GetFuncCalls())
+
+	// Calling our functions should fill their results and record each call. Error results are only set to non-nil
+	// errors when the data decides so, which it can't once exhausted.
+	ok, err := c.Validate("input", 3)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	c.OnEvent(1, 2)
+
+	// Results which cannot be filled are left as zero values, and the error is recorded.
+	ok, err = c.Validate("again", 4)
+	assert.False(t, ok)
+	assert.Nil(t, err)
+
+	// Verify our call log.
+	calls := tp.GetFuncCalls()
+	assert.Len(t, calls, 3)
+	assert.EqualValues(t, reflect.TypeOf(c.Validate), calls[0].Type)
+	assert.EqualValues(t, []interface{}{"input", 3}, calls[0].Args)
+	assert.EqualValues(t, []interface{}{true, nil}, calls[0].Results)
+	assert.Nil(t, calls[0].Err)
+	assert.EqualValues(t, []interface{}{[]uint8{1, 2}}, calls[1].Args)
+	assert.Empty(t, calls[1].Results)
+	assert.EqualValues(t, []interface{}{"again", 4}, calls[2].Args)
+	assert.True(t, errors.Is(calls[2].Err, go_fuzz_utils.ErrEndOfStream))
+	var fillErr *go_fuzz_utils.FillError
+	assert.True(t, errors.As(calls[2].Err, &fillErr))
+	assert.EqualValues(t, "(result 0)", fillErr.Path)
+
+	// Clearing or resetting removes our calls.
+	tp.ClearFuncCalls()
+	assert.Empty(t, tp.GetFuncCalls())
+	c.OnEvent()
+	assert.Len(t, tp.GetFuncCalls(), 1)
+	assert.Nil(t, tp.Reset())
+	assert.Empty(t, tp.GetFuncCalls())
+
+	// The common nil bias applies to functions, and invalid biases return an error.
+	assert.Nil(t, tp.SetParamsBiasesCommon(1, 0))
+	assert.EqualValues(t, 1, tp.GetParamsFuncNilBias())
+	assert.Nil(t, tp.Fill(&c))
+	assert.Nil(t, c.Validate)
+	assert.True(t, errors.Is(tp.SetParamsFuncNilBias(-1), go_fuzz_utils.ErrInvalidParam))
+}
+
+// codeError describes an error type which can be registered as an implementation of error.
+type codeError struct {
+	Code uint8
+}
+
+func (e codeError) Error() string { return "code error" }
+
+func TestFillFuncErrors(t *testing.T) {
+	// Create our type provider, with data for our nil decision followed by data for an error and its message.
+	tp, err := go_fuzz_utils.NewTypeProvider([]byte{0xFF, 0x00, 0x03, 'b', 'a', 'd', 0xFF})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0.25, tp.GetParamsFuncErrorBias())
+
+	// Our function should return a non-nil error, followed by a nil error.
+	var f func(int) error
+	assert.Nil(t, tp.Fill(&f))
+	assert.NotNil(t, f)
+	err = f(1)
+	assert.NotNil(t, err)
+	assert.NotEmpty(t, err.Error())
+	assert.Nil(t, f(2))
+	calls := tp.GetFuncCalls()
+	assert.Len(t, calls, 2)
+	assert.EqualValues(t, []interface{}{err}, calls[0].Results)
+	assert.Nil(t, calls[0].Err)
+
+	// With a full error bias, every call should return an error.
+	assert.Nil(t, tp.Reset())
+	assert.Nil(t, tp.SetParamsFuncErrorBias(1))
+	assert.Nil(t, tp.Fill(&f))
+	assert.NotNil(t, f(1))
+
+	// Errors are filled like other interfaces once implementations of error are registered.
+	assert.Nil(t, tp.Reset())
+	assert.Nil(t, go_fuzz_utils.RegisterImplementationsOf[error](tp, codeError{}))
+	assert.Nil(t, tp.Fill(&f))
+	err = f(1)
+	assert.True(t, err == nil || errors.As(err, &codeError{}))
+
+	// Invalid biases return an error.
+	assert.True(t, errors.Is(tp.SetParamsFuncErrorBias(1.5), go_fuzz_utils.ErrInvalidParam))
+}
+
+func TestEncodeFuncs(t *testing.T) {
+	tp, err := go_fuzz_utils.NewTypeProvider(nil)
+	assert.Nil(t, err)
+
+	// Functions are encoded as synthesized or nil.
+	value := callbacks{OnEvent: func(...uint8) {}}
+	data, err := tp.Encode(&value)
+	assert.Nil(t, err)
+	tp, err = go_fuzz_utils.NewTypeProvider(data)
+	assert.Nil(t, err)
+	var filled callbacks
+	assert.Nil(t, tp.Fill(&filled))
+	assert.Nil(t, filled.Validate)
+	assert.NotNil(t, filled.OnEvent)
+	assert.Nil(t, filled.Optional)
+
+	// Functions cannot be encoded as nil with a zero nil bias.
+	assert.Nil(t, tp.SetParamsFuncNilBias(0))
+	_, err = tp.Encode(&value)
+	assert.NotNil(t, err)
+}
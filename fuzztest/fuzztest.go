@@ -0,0 +1,75 @@
+// Package fuzztest integrates TypeProvider with the native Go fuzzing engine (`go test -fuzz`) and other tests. It is
+// kept separate from the go_fuzz_utils package so that programs using a TypeProvider do not depend on the testing
+// package.
+package fuzztest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+// Fuzz registers a fuzz target with the native Go fuzzing engine (`go test -fuzz`). Each input produced by the fuzzer
+// is used to construct a TypeProvider which is passed to the provided function. Any provided seeds are added to the
+// seed corpus before the fuzz target is registered.
+func Fuzz(f *testing.F, fn func(t *testing.T, tp *go_fuzz_utils.TypeProvider), seeds ...[]byte) {
+	f.Helper()
+
+	// Register our seed corpus entries
+	AddSeeds(f, seeds...)
+
+	// Register our fuzz target, constructing a TypeProvider from every input.
+	f.Fuzz(func(t *testing.T, data []byte) {
+		tp := NewTypeProviderT(t, data)
+		fn(t, tp)
+	})
+}
+
+// AddSeeds adds the provided inputs to the seed corpus of a native Go fuzz test.
+func AddSeeds(f *testing.F, seeds ...[]byte) {
+	f.Helper()
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+}
+
+// NewTypeProviderT constructs a new TypeProvider for use within a native Go fuzz test or unit test. If the provided
+// data is not sufficient to construct a TypeProvider, the test is skipped so the fuzzer can continue to the next input.
+// Any other error fails the test. If the test fails, the fill parameters of the TypeProvider are logged to aid
+// reproduction.
+// Returns the newly constructed TypeProvider.
+func NewTypeProviderT(tb testing.TB, data []byte) *go_fuzz_utils.TypeProvider {
+	tb.Helper()
+
+	// Construct our type provider, skipping the test if we did not have enough data.
+	tp, err := go_fuzz_utils.NewTypeProvider(data)
+	if errors.Is(err, go_fuzz_utils.ErrEndOfStream) {
+		tb.Skipf("could not construct TypeProvider: %v", err)
+	} else if err != nil {
+		tb.Fatalf("could not construct TypeProvider: %v", err)
+	}
+
+	// Report our parameters if the test fails, as they're needed to reproduce the failing values.
+	tb.Cleanup(func() {
+		if tb.Failed() {
+			tb.Logf("TypeProvider parameters: %s", tp.ParamsString())
+		}
+	})
+	return tp
+}
+
+// FillOrSkip populates data into a variable at a provided pointer using Fill. If the data is not sufficient to fill
+// the variable, the test is skipped so the fuzzer can continue to the next input. Any other error, such as an invalid
+// struct tag, fails the test.
+func FillOrSkip(tb testing.TB, tp *go_fuzz_utils.TypeProvider, i interface{}) {
+	tb.Helper()
+
+	// Fill our value, skipping the test if we did not have enough data.
+	err := tp.Fill(i)
+	if errors.Is(err, go_fuzz_utils.ErrEndOfStream) {
+		tb.Skipf("could not fill %T: %v", i, err)
+	} else if err != nil {
+		tb.Fatalf("could not fill %T: %v", i, err)
+	}
+}
@@ -0,0 +1,58 @@
+package fuzztest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+	"github.com/trailofbits/go-fuzz-utils/fuzztest"
+)
+
+// fuzzStruct describes a struct filled by our fuzz target.
+type fuzzStruct struct {
+	Name   string
+	Values []uint32
+}
+
+// generateTestData generates descending bytes of the provided length to use as input data.
+func generateTestData(length uint) []byte {
+	b := make([]byte, length)
+	for i := 0; i < len(b); i++ {
+		b[i] = 255 - byte(i%256)
+	}
+	return b
+}
+
+func FuzzTypeProvider(f *testing.F) {
+	// Register a fuzz target with a few seeds, which are executed as regular tests when not fuzzing.
+	fuzztest.Fuzz(f, func(t *testing.T, tp *go_fuzz_utils.TypeProvider) {
+		assert.Nil(t, tp.SetParamsSliceBounds(0, 3))
+
+		// Fill a structure, which should be skipped if we run out of data.
+		st := fuzzStruct{}
+		fuzztest.FillOrSkip(t, tp, &st)
+		assert.LessOrEqual(t, len(st.Values), 3)
+	}, generateTestData(0x1000), generateTestData(16))
+}
+
+func TestNewTypeProviderTSkips(t *testing.T) {
+	// Construct a type provider, but fill a value which requires more data than what remains.
+	reached := false
+	t.Run("skipFill", func(t *testing.T) {
+		tp := fuzztest.NewTypeProviderT(t, generateTestData(7))
+		var u64 uint64
+		fuzztest.FillOrSkip(t, tp, &u64)
+		reached = true
+	})
+	assert.False(t, reached)
+
+	// Construct a type provider with enough data and fill a value successfully.
+	reached = false
+	t.Run("fill", func(t *testing.T) {
+		tp := fuzztest.NewTypeProviderT(t, generateTestData(8))
+		var u64 uint64
+		fuzztest.FillOrSkip(t, tp, &u64)
+		reached = true
+	})
+	assert.True(t, reached)
+}
@@ -0,0 +1,103 @@
+package go_fuzz_utils
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Get obtains a value of the provided type, populated in the same manner as Fill.
+// Returns the populated value, or an error if one is encountered.
+func Get[T any](tp *TypeProvider) (T, error) {
+	// Create our value and fill it through its address.
+	var v T
+	err := FillT(tp, &v)
+	return v, err
+}
+
+// GetSlice obtains a slice of the provided element type, populated in the same manner as Fill. The slice size and
+// nil probability are determined by the TypeProvider's slice parameters.
+// Returns the populated slice, or an error if one is encountered.
+func GetSlice[T any](tp *TypeProvider) ([]T, error) {
+	return Get[[]T](tp)
+}
+
+// GetMap obtains a map of the provided key and value types, populated in the same manner as Fill. The map size and nil
+// probability are determined by the TypeProvider's map parameters.
+// Returns the populated map, or an error if one is encountered.
+func GetMap[K comparable, V any](tp *TypeProvider) (map[K]V, error) {
+	return Get[map[K]V](tp)
+}
+
+// FillT populates data into the variable at the provided pointer. This is a type-safe equivalent of Fill.
+// Returns an error if one is encountered.
+func FillT[T any](tp *TypeProvider, v *T) error {
+	return tp.withAliases(func() error {
+		return tp.fillValue(reflect.ValueOf(v).Elem(), 0, nil)
+	})
+}
+
+// RegisterImplementationsOf registers the dynamic types of the provided values as concrete types which Fill may use to
+// populate values of the interface type I. See RegisterImplementations for more details.
+// Returns an error if I is not an interface type, or if any provided value is nil.
+func RegisterImplementationsOf[I any](tp *TypeProvider, impls ...I) error {
+	// Obtain the dynamic type of each provided value.
+	implTypes := make([]reflect.Type, len(impls))
+	for i, impl := range impls {
+		implTypes[i] = reflect.TypeOf(impl)
+	}
+	return tp.RegisterImplementations(reflect.TypeOf((*I)(nil)).Elem(), implTypes...)
+}
+
+// RegisterFillerOf registers a custom function which Fill uses to populate values of the type T. See RegisterFiller
+// for more details.
+// Returns an error if the provided function is nil.
+func RegisterFillerOf[T any](tp *TypeProvider, fn func(tp *TypeProvider) (T, error)) error {
+	// Validate our function, as it is wrapped below.
+	if fn == nil {
+		return tp.RegisterFiller(reflect.TypeOf((*T)(nil)).Elem(), nil)
+	}
+
+	// Register a function which obtains our value and sets it.
+	return tp.RegisterFiller(reflect.TypeOf((*T)(nil)).Elem(), func(tp *TypeProvider, v reflect.Value) error {
+		x, err := fn(tp)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(&x).Elem())
+		return nil
+	})
+}
+
+// PickOne obtains one of the provided options, chosen by PickIndex.
+// Returns the chosen option, or an error if no options were provided or the end of stream has been reached.
+func PickOne[T any](tp *TypeProvider, options []T) (T, error) {
+	// Choose the index of our option.
+	var option T
+	i, err := tp.PickIndex(len(options))
+	if err != nil {
+		return option, err
+	}
+	return options[i], nil
+}
+
+// PickWeighted obtains one of the provided options, chosen by PickWeightedIndex with the provided weights for each
+// option.
+// Returns the chosen option, or an error if the weights are invalid or do not match the options, or the end of stream
+// has been reached.
+func PickWeighted[T any](tp *TypeProvider, options []T, weights []uint) (T, error) {
+	// Validate we have a weight for each option.
+	var option T
+	if len(options) != len(weights) {
+		return option, &InvalidParamError{
+			Param:  "weights",
+			Reason: fmt.Sprintf("%d weights provided for %d options", len(weights), len(options)),
+		}
+	}
+
+	// Choose the index of our option.
+	i, err := tp.PickWeightedIndex(weights)
+	if err != nil {
+		return option, err
+	}
+	return options[i], nil
+}
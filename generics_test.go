@@ -0,0 +1,105 @@
+package go_fuzz_utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+func TestGetStructs(t *testing.T) {
+	// Create our fuzz data
+	b := generateTestData(0x1000)
+
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider(b)
+	assert.Nil(t, err)
+
+	// Obtain a test structure.
+	err = tp.SetParamsBiasesCommon(0, 0)
+	assert.Nil(t, err)
+	tp.SetParamsFillUnexportedFields(true)
+	st, err := go_fuzz_utils.Get[testStruct](tp)
+
+	// Ensure no error was encountered and private variables were filled in this instance.
+	assert.Nil(t, err)
+	assert.NotNil(t, st.sArr)                                           // private variable, filled
+	assert.NotNil(t, st.bArr)                                           // private variable, filled
+	assert.False(t, st.st1.s == "" && st.st1.s2 == "" && st.st1.i == 0) // depth 2, something should be non-default value.
+
+	// Reset our provider state
+	err = tp.Reset()
+	assert.Nil(t, err)
+
+	// Fill a test structure, which should produce the same value as Fill given the same provider state.
+	st2 := testStruct{}
+	err = go_fuzz_utils.FillT(tp, &st2)
+	assert.Nil(t, err)
+	assert.EqualValues(t, st.sArr, st2.sArr)
+	assert.EqualValues(t, st.bArr, st2.bArr)
+	assert.EqualValues(t, st.st1, st2.st1)
+
+	// Reset our provider state
+	err = tp.Reset()
+	assert.Nil(t, err)
+
+	// Obtain a test structure without unexported fields.
+	tp.SetParamsFillUnexportedFields(false)
+	st3, err := go_fuzz_utils.Get[testStruct](tp)
+
+	// Ensure no error was encountered and private variables weren't filled in this instance.
+	assert.Nil(t, err)
+	assert.Nil(t, st3.sArr)               // private variable, unfilled
+	assert.Nil(t, st3.bArr)               // private variable, unfilled
+	assert.EqualValues(t, "", st3.st1.s)  // private variable, unfilled
+	assert.EqualValues(t, "", st3.st1.s2) // private variable, unfilled
+	assert.EqualValues(t, 0, st3.st1.i)   // private variable, unfilled
+
+	// Obtain a pointer to a test structure.
+	stPtr, err := go_fuzz_utils.Get[*testStruct](tp)
+	assert.Nil(t, err)
+	assert.NotNil(t, stPtr)
+}
+
+func TestGetComplexTypes(t *testing.T) {
+	// Create our fuzz data
+	b := generateTestData(0x1000)
+
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider(b)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsBiasesCommon(0, 0))
+
+	// Obtain a mapping.
+	m, err := go_fuzz_utils.GetMap[string, int](tp)
+
+	// Ensure something was generated.
+	assert.Nil(t, err)
+	assert.NotNil(t, m)
+	assert.LessOrEqual(t, len(m), 15) // no more than 15 entries based on our args
+
+	// Obtain a slice.
+	u64Arr, err := go_fuzz_utils.GetSlice[uint64](tp)
+
+	// Ensure something was generated.
+	assert.Nil(t, err)
+	assert.NotNil(t, u64Arr)
+	assert.LessOrEqual(t, len(u64Arr), 15) // no more than 15 entries based on our args
+
+	// Obtain a slice of mappings.
+	assert.Nil(t, tp.SetParamsMapBounds(1, 1))
+	assert.Nil(t, tp.SetParamsSliceBounds(3, 3))
+	mappingArr, err := go_fuzz_utils.GetSlice[map[string]int](tp)
+
+	// Ensure something was generated.
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, len(mappingArr))
+	for _, mapping := range mappingArr {
+		assert.EqualValues(t, 1, len(mapping))
+	}
+
+	// Obtain an array, which has a fixed size regardless of our slice bounds.
+	arr, err := go_fuzz_utils.Get[[4]int16](tp)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 4, len(arr))
+}
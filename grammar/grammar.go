@@ -0,0 +1,372 @@
+// Package grammar generates structured text, such as expressions, queries or configuration files, from a grammar
+// using the choices of a go_fuzz_utils.TypeProvider. Grammars are made of named rules, which are defined in Go using
+// the node constructors of this package, or loaded from an EBNF-like text format using Parse.
+//
+// Expansion only depends on the grammar, its depth limit and the TypeProvider's data and parameters, so the same input
+// always produces the same string.
+package grammar
+
+import (
+	"fmt"
+	"math"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+// defaultDepthLimit describes the default maximum depth of nested rule references when expanding a grammar.
+const defaultDepthLimit = 32
+
+// Node describes an element of a grammar which expands to a string.
+type Node interface {
+	// validate verifies the node and the nodes within it are valid for the provided grammar.
+	validate(g *Grammar) error
+	// height obtains the minimum depth of nested rule references needed to expand the node, given the grammar's known
+	// rule heights. Nodes which cannot be expanded have a height of math.MaxInt.
+	height(g *Grammar) int
+	// expand writes an expansion of the node to the provided builder, using no more than the provided depth of nested
+	// rule references.
+	expand(g *Grammar, tp *go_fuzz_utils.TypeProvider, sb *strings.Builder, budget int) error
+}
+
+// Rules describes the rules of a grammar, mapping rule names to the node each rule expands.
+type Rules map[string]Node
+
+// Grammar describes a set of rules which expand to strings, starting from a start rule.
+type Grammar struct {
+	// rules describes the rules of the grammar.
+	rules Rules
+	// start describes the name of the rule expansion starts from.
+	start string
+	// depthLimit describes the maximum depth of nested rule references when expanding the grammar. A value of zero
+	// indicates unlimited depth.
+	depthLimit int
+	// heights describes the minimum depth of nested rule references needed to expand each rule.
+	heights map[string]int
+}
+
+// New constructs a new Grammar from the provided rules, which expands the rule with the provided start name. The depth
+// limit defaults to 32.
+// Returns the newly constructed Grammar, or an error if a rule is invalid, refers to an undefined rule, or can never
+// finish expanding.
+func New(start string, rules Rules) (*Grammar, error) {
+	// Create our grammar and verify our start rule exists.
+	g := &Grammar{rules: rules, start: start, depthLimit: defaultDepthLimit}
+	if _, ok := rules[start]; !ok {
+		return nil, fmt.Errorf("start rule %q is not defined", start)
+	}
+
+	// Validate each of our rules.
+	for name, node := range rules {
+		if node == nil {
+			return nil, fmt.Errorf("rule %q is nil", name)
+		}
+		if err := node.validate(g); err != nil {
+			return nil, fmt.Errorf("rule %q is invalid: %v", name, err)
+		}
+	}
+
+	// Determine the height of each rule. Rules start out unexpandable, and we repeatedly update their heights from the
+	// rules they refer to until nothing changes.
+	g.heights = make(map[string]int, len(rules))
+	for name := range rules {
+		g.heights[name] = math.MaxInt
+	}
+	for changed := true; changed; {
+		changed = false
+		for name, node := range rules {
+			if h := node.height(g); h < g.heights[name] {
+				g.heights[name] = h
+				changed = true
+			}
+		}
+	}
+
+	// Verify every rule can finish expanding.
+	for name, h := range g.heights {
+		if h == math.MaxInt {
+			return nil, fmt.Errorf("rule %q can never finish expanding", name)
+		}
+	}
+	return g, nil
+}
+
+// GetDepthLimit obtains the maximum depth of nested rule references when expanding the grammar. A value of zero
+// indicates unlimited depth.
+func (g *Grammar) GetDepthLimit() int {
+	return g.depthLimit
+}
+
+// SetDepthLimit sets the maximum depth of nested rule references when expanding the grammar, analogous to the depth
+// limit of a TypeProvider. Once the limit would be exceeded, only alternatives and repetitions which can finish
+// expanding within the remaining depth are chosen. A value of zero indicates unlimited depth, in which case expansion
+// of recursive grammars is only bounded by the input data.
+// Returns an error if the depth limit is negative, or too small to expand the start rule.
+func (g *Grammar) SetDepthLimit(depthLimit int) error {
+	// Validate our parameters and set them accordingly
+	if depthLimit < 0 {
+		return &go_fuzz_utils.InvalidParamError{Param: "depth limit", Reason: "depth limit cannot be negative"}
+	}
+	if depthLimit != 0 && depthLimit <= g.heights[g.start] {
+		return &go_fuzz_utils.InvalidParamError{
+			Param:  "depth limit",
+			Reason: fmt.Sprintf("start rule %q needs a depth limit of at least %d", g.start, g.heights[g.start]+1),
+		}
+	}
+	g.depthLimit = depthLimit
+	return nil
+}
+
+// Generate expands the grammar's start rule to a string, using the provided TypeProvider to make each choice.
+// Returns the generated string, or an error if the end of stream has been reached.
+func (g *Grammar) Generate(tp *go_fuzz_utils.TypeProvider) (string, error) {
+	// Determine our depth budget, and expand our start rule with it.
+	budget := math.MaxInt
+	if g.depthLimit > 0 {
+		budget = g.depthLimit
+	}
+	var sb strings.Builder
+	err := Ref(g.start).expand(g, tp, &sb, budget)
+	if err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// literal describes a node which expands to a fixed string.
+type literal struct {
+	value string
+}
+
+// Lit creates a node which expands to the provided string.
+func Lit(value string) Node {
+	return &literal{value: value}
+}
+
+func (n *literal) validate(g *Grammar) error {
+	return nil
+}
+
+func (n *literal) height(g *Grammar) int {
+	return 0
+}
+
+func (n *literal) expand(g *Grammar, tp *go_fuzz_utils.TypeProvider, sb *strings.Builder, budget int) error {
+	sb.WriteString(n.value)
+	return nil
+}
+
+// pattern describes a node which expands to a string matching a regular expression.
+type pattern struct {
+	pattern string
+}
+
+// Pattern creates a node which expands to a string matching the provided regular expression, in the syntax accepted by
+// the regexp package, as generated by TypeProvider.GetStringMatching.
+func Pattern(p string) Node {
+	return &pattern{pattern: p}
+}
+
+func (n *pattern) validate(g *Grammar) error {
+	_, err := syntax.Parse(n.pattern, syntax.Perl)
+	return err
+}
+
+func (n *pattern) height(g *Grammar) int {
+	return 0
+}
+
+func (n *pattern) expand(g *Grammar, tp *go_fuzz_utils.TypeProvider, sb *strings.Builder, budget int) error {
+	s, err := tp.GetStringMatching(n.pattern)
+	if err != nil {
+		return err
+	}
+	sb.WriteString(s)
+	return nil
+}
+
+// reference describes a node which expands a rule of the grammar.
+type reference struct {
+	name string
+}
+
+// Ref creates a node which expands the grammar rule with the provided name. Each nested reference counts towards the
+// grammar's depth limit.
+func Ref(name string) Node {
+	return &reference{name: name}
+}
+
+func (n *reference) validate(g *Grammar) error {
+	if _, ok := g.rules[n.name]; !ok {
+		return fmt.Errorf("rule %q is not defined", n.name)
+	}
+	return nil
+}
+
+func (n *reference) height(g *Grammar) int {
+	h := g.heights[n.name]
+	if h == math.MaxInt {
+		return h
+	}
+	return h + 1
+}
+
+func (n *reference) expand(g *Grammar, tp *go_fuzz_utils.TypeProvider, sb *strings.Builder, budget int) error {
+	return g.rules[n.name].expand(g, tp, sb, budget-1)
+}
+
+// sequence describes a node which expands each of its nodes in order.
+type sequence struct {
+	nodes []Node
+}
+
+// Seq creates a node which expands each of the provided nodes in order.
+func Seq(nodes ...Node) Node {
+	return &sequence{nodes: nodes}
+}
+
+func (n *sequence) validate(g *Grammar) error {
+	return validateNodes(g, n.nodes)
+}
+
+func (n *sequence) height(g *Grammar) int {
+	// Our height is that of our highest node.
+	h := 0
+	for _, node := range n.nodes {
+		if nodeHeight := node.height(g); nodeHeight > h {
+			h = nodeHeight
+		}
+	}
+	return h
+}
+
+func (n *sequence) expand(g *Grammar, tp *go_fuzz_utils.TypeProvider, sb *strings.Builder, budget int) error {
+	for _, node := range n.nodes {
+		if err := node.expand(g, tp, sb, budget); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// alternation describes a node which expands one of its nodes.
+type alternation struct {
+	nodes []Node
+}
+
+// Alt creates a node which expands one of the provided nodes, chosen as done by TypeProvider.PickIndex.
+func Alt(nodes ...Node) Node {
+	return &alternation{nodes: nodes}
+}
+
+func (n *alternation) validate(g *Grammar) error {
+	if len(n.nodes) == 0 {
+		return fmt.Errorf("alternation has no alternatives")
+	}
+	return validateNodes(g, n.nodes)
+}
+
+func (n *alternation) height(g *Grammar) int {
+	// Our height is that of our lowest node.
+	h := math.MaxInt
+	for _, node := range n.nodes {
+		if nodeHeight := node.height(g); nodeHeight < h {
+			h = nodeHeight
+		}
+	}
+	return h
+}
+
+func (n *alternation) expand(g *Grammar, tp *go_fuzz_utils.TypeProvider, sb *strings.Builder, budget int) error {
+	// Determine which alternatives can finish expanding within our budget.
+	candidates := make([]Node, 0, len(n.nodes))
+	for _, node := range n.nodes {
+		if node.height(g) <= budget {
+			candidates = append(candidates, node)
+		}
+	}
+
+	// Choose one of our candidates and expand it.
+	i, err := tp.PickIndex(len(candidates))
+	if err != nil {
+		return err
+	}
+	return candidates[i].expand(g, tp, sb, budget)
+}
+
+// repetition describes a node which expands another node a number of times.
+type repetition struct {
+	node Node
+	min  int
+	max  int
+}
+
+// Repeat creates a node which expands the provided node a number of times within the inclusive range [min, max],
+// chosen as done by TypeProvider.GetIntInRange. A negative max indicates the node may repeat any number of times, in
+// which case the maximum is the TypeProvider's maximum slice size.
+func Repeat(node Node, min int, max int) Node {
+	return &repetition{node: node, min: min, max: max}
+}
+
+// Opt creates a node which expands the provided node zero or one times.
+func Opt(node Node) Node {
+	return Repeat(node, 0, 1)
+}
+
+func (n *repetition) validate(g *Grammar) error {
+	if n.min < 0 || (n.max >= 0 && n.max < n.min) {
+		return fmt.Errorf("repetition has invalid bounds [%d, %d]", n.min, n.max)
+	}
+	return validateNodes(g, []Node{n.node})
+}
+
+func (n *repetition) height(g *Grammar) int {
+	// If we can repeat zero times, we don't need to expand our node.
+	if n.min == 0 {
+		return 0
+	}
+	return n.node.height(g)
+}
+
+func (n *repetition) expand(g *Grammar, tp *go_fuzz_utils.TypeProvider, sb *strings.Builder, budget int) error {
+	// If our node can't finish expanding within our budget, we must repeat it zero times.
+	if n.node.height(g) > budget {
+		return nil
+	}
+
+	// Determine our bounds, limiting unbounded repetitions to the maximum slice size.
+	maxCount := n.max
+	if maxCount < 0 {
+		_, maxCount = tp.GetParamsSliceBounds()
+		if maxCount < n.min {
+			maxCount = n.min
+		}
+	}
+
+	// Decide how many times to repeat our node, and expand it that many times.
+	count, err := tp.GetIntInRange(n.min, maxCount)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < count; i++ {
+		if err := n.node.expand(g, tp, sb, budget); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateNodes verifies each of the provided nodes is valid for the provided grammar.
+// Returns an error if any node is invalid.
+func validateNodes(g *Grammar, nodes []Node) error {
+	for _, node := range nodes {
+		if node == nil {
+			return fmt.Errorf("node is nil")
+		}
+		if err := node.validate(g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package grammar_test
+
+import (
+	"errors"
+	"go/parser"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+	"github.com/trailofbits/go-fuzz-utils/grammar"
+)
+
+// expressionGrammar describes arithmetic expressions which are valid Go expressions.
+const expressionGrammar = `
+# Arithmetic expressions
+expr   = term { (" + " | " - ") term } ;
+term   = factor { (' * ' | ' / ') factor } ;
+factor = number | "(" expr ")" | "-(" expr ")" ;
+number = /[1-9][0-9]{0,3}/ ;
+`
+
+// newExpressionGrammar parses our expression grammar, limiting its depth so expressions stay small.
+func newExpressionGrammar(t *testing.T) *grammar.Grammar {
+	g, err := grammar.Parse(expressionGrammar)
+	assert.Nil(t, err)
+	err = g.SetDepthLimit(8)
+	assert.Nil(t, err)
+	return g
+}
+
+// newRandomTypeProvider creates a type provider backed by deterministic pseudo-random data, limiting repetitions so
+// expressions stay small.
+func newRandomTypeProvider(t *testing.T, seed int64) *go_fuzz_utils.TypeProvider {
+	tp, err := go_fuzz_utils.NewTypeProvider(generateRandomData(seed, 0x1000))
+	assert.Nil(t, err)
+	err = tp.SetParamsSliceBounds(0, 3)
+	assert.Nil(t, err)
+	return tp
+}
+
+// generateRandomData creates deterministic pseudo-random data of the provided length for use in tests.
+func generateRandomData(seed int64, length int) []byte {
+	b := make([]byte, length)
+	rand.New(rand.NewSource(seed)).Read(b)
+	return b
+}
+
+func TestGrammarGenerate(t *testing.T) {
+	// Define a grammar in Go.
+	g, err := grammar.New("greeting", grammar.Rules{
+		"greeting": grammar.Seq(grammar.Ref("word"), grammar.Lit(", "), grammar.Ref("name"), grammar.Opt(grammar.Lit("!"))),
+		"word":     grammar.Alt(grammar.Lit("hello"), grammar.Lit("goodbye"), grammar.Lit("hi")),
+		"name":     grammar.Repeat(grammar.Lit("a"), 1, 3),
+	})
+	assert.Nil(t, err)
+
+	// Each choice consumes a byte and a byte of slack, scaled into the range of choices.
+	tp, err := go_fuzz_utils.NewTypeProvider([]byte{0x80, 0x00, 0x80, 0x00, 0x80, 0x00})
+	assert.Nil(t, err)
+	s, err := g.Generate(tp)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "goodbye, aa!", s)
+
+	// Running out of data returns an error.
+	_, err = g.Generate(tp)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrEndOfStream))
+}
+
+func TestGrammarParse(t *testing.T) {
+	// Parse our expression grammar.
+	g := newExpressionGrammar(t)
+
+	// Every generated expression should be a valid Go expression.
+	for i := int64(0); i < 100; i++ {
+		s, err := g.Generate(newRandomTypeProvider(t, i))
+		assert.Nil(t, err)
+		_, err = parser.ParseExpr(s)
+		assert.Nil(t, err, "generated invalid expression %q", s)
+	}
+
+	// Alternative syntax is supported.
+	g, err := grammar.Parse(`list ::= "[" [ item ( "," item )* ] "]" ; item ::= 'x'+ | "世" ;`)
+	assert.Nil(t, err)
+	tp, err := go_fuzz_utils.NewTypeProvider([]byte{0x80, 0x00, 0x80, 0x00, 0x10, 0x00, 0x00, 0x00, 0x11, 0x12})
+	assert.Nil(t, err)
+	s, err := g.Generate(tp)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "[世,xx]", s)
+
+	// Invalid definitions return an error.
+	invalid := []string{
+		``,
+		`a = "x"`,
+		`a "x" ;`,
+		`a = ( "x" ;`,
+		`a = "x ;`,
+		`a = /x ;`,
+		`a = /(/ ;`,
+		`a = b ;`,
+		`a = "x" ; a = "y" ;`,
+		`a = "x" a ;`,
+		`a = @ ;`,
+	}
+	for _, text := range invalid {
+		_, err = grammar.Parse(text)
+		assert.NotNil(t, err, "parsed invalid grammar %q", text)
+	}
+}
+
+func TestGrammarDepthLimit(t *testing.T) {
+	// Parse a grammar which always picks its recursive alternative first.
+	g, err := grammar.Parse(`nest = "(" nest ")" | "x" ;`)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 32, g.GetDepthLimit())
+
+	// Once our depth limit is reached, only alternatives which can finish expanding are chosen.
+	err = g.SetDepthLimit(4)
+	assert.Nil(t, err)
+	tp, err := go_fuzz_utils.NewTypeProvider(nil)
+	assert.Nil(t, err)
+	err = tp.SetParamsExhaustionPolicy(go_fuzz_utils.ExhaustionPolicyZero)
+	assert.Nil(t, err)
+	s, err := g.Generate(tp)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "(((x)))", s)
+
+	// Depth limits which cannot expand the start rule are rejected.
+	err = g.SetDepthLimit(-1)
+	assert.NotNil(t, err)
+	err = g.SetDepthLimit(0)
+	assert.Nil(t, err)
+	g, err = grammar.Parse(`outer = "(" inner ")" ; inner = "x" ;`)
+	assert.Nil(t, err)
+	err = g.SetDepthLimit(1)
+	assert.NotNil(t, err)
+	err = g.SetDepthLimit(2)
+	assert.Nil(t, err)
+
+	// Grammars which can never finish expanding are rejected.
+	_, err = grammar.Parse(`a = "(" a ")" ;`)
+	assert.NotNil(t, err)
+}
+
+func TestGrammarDeterministic(t *testing.T) {
+	// Parse our expression grammar.
+	g := newExpressionGrammar(t)
+
+	// Generating from the same data should always produce the same string.
+	var results []string
+	for i := 0; i < 5; i++ {
+		s, err := g.Generate(newRandomTypeProvider(t, 1))
+		assert.Nil(t, err)
+		results = append(results, s)
+	}
+	assert.EqualValues(t, strings.Repeat(results[0], 5), strings.Join(results, ""))
+}
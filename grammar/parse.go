@@ -0,0 +1,327 @@
+package grammar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Parse constructs a new Grammar from a text definition in an EBNF-like format. The first rule defined is the start
+// rule. Each rule has the form `name = expression ;`, where `::=` may be used in place of `=`. Expressions are made of:
+//   - "text" or 'text': a literal string. Double quoted strings support Go escape sequences.
+//   - /regex/: a string matching a regular expression, as generated by TypeProvider.GetStringMatching.
+//   - name: a reference to another rule.
+//   - a b: a sequence, expanding a then b.
+//   - a | b: an alternation, expanding either a or b.
+//   - ( a ): a group.
+//   - [ a ] or a?: an optional element, expanded zero or one times.
+//   - { a } or a*: an element expanded zero or more times.
+//   - a+: an element expanded one or more times.
+//
+// Comments start with a '#' and continue until the end of the line.
+// Returns the newly constructed Grammar, or an error if the definition could not be parsed or the grammar is invalid.
+func Parse(text string) (*Grammar, error) {
+	// Tokenize our text.
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse each rule until we reach the end of our tokens.
+	p := &parser{tokens: tokens}
+	rules := make(Rules)
+	start := ""
+	for p.peek().kind != tokenEOF {
+		// Parse our rule name and definition operator.
+		name := p.next()
+		if name.kind != tokenIdent {
+			return nil, p.errorf(name, "expected rule name, got %s", name)
+		}
+		if op := p.next(); op.kind != tokenDefine {
+			return nil, p.errorf(op, "expected '=' after rule name %q, got %s", name.text, op)
+		}
+
+		// Parse our rule's expression, followed by the terminator.
+		node, err := p.parseAlternation()
+		if err != nil {
+			return nil, err
+		}
+		if end := p.next(); end.kind != tokenSymbol || end.text != ";" {
+			return nil, p.errorf(end, "expected ';' after rule %q, got %s", name.text, end)
+		}
+
+		// Add our rule, ensuring it was not already defined. The first rule is our start rule.
+		if _, ok := rules[name.text]; ok {
+			return nil, p.errorf(name, "rule %q is defined more than once", name.text)
+		}
+		rules[name.text] = node
+		if start == "" {
+			start = name.text
+		}
+	}
+
+	// Verify we defined at least one rule and construct our grammar.
+	if start == "" {
+		return nil, fmt.Errorf("grammar defines no rules")
+	}
+	return New(start, rules)
+}
+
+// tokenKind describes the kind of a token in a text grammar definition.
+type tokenKind int
+
+const (
+	// tokenEOF describes the end of the definition.
+	tokenEOF tokenKind = iota
+	// tokenIdent describes a rule name.
+	tokenIdent
+	// tokenDefine describes a rule definition operator.
+	tokenDefine
+	// tokenLiteral describes a literal string, with any quotes and escapes removed.
+	tokenLiteral
+	// tokenPattern describes a regular expression, with its delimiters removed.
+	tokenPattern
+	// tokenSymbol describes a single character operator or delimiter.
+	tokenSymbol
+)
+
+// token describes a token in a text grammar definition.
+type token struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+// String obtains a description of the token for use in error messages.
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of input"
+	case tokenIdent:
+		return fmt.Sprintf("rule name %q", t.text)
+	case tokenLiteral:
+		return fmt.Sprintf("literal %q", t.text)
+	case tokenPattern:
+		return fmt.Sprintf("pattern /%s/", t.text)
+	default:
+		return fmt.Sprintf("'%s'", t.text)
+	}
+}
+
+// tokenize splits a text grammar definition into tokens, ending with a tokenEOF.
+// Returns the tokens, or an error if the definition contains an invalid or unterminated token.
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	line := 1
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\n':
+			// Track our line number for error messages.
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#':
+			// Skip comments until the end of the line.
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+		case c == '_' || unicode.IsLetter(rune(c)):
+			// Read an identifier, which may contain letters, digits, underscores and hyphens.
+			j := i + 1
+			for j < len(text) && (text[j] == '_' || text[j] == '-' || unicode.IsLetter(rune(text[j])) ||
+				unicode.IsDigit(rune(text[j]))) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: text[i:j], line: line})
+			i = j
+		case c == '=':
+			tokens = append(tokens, token{kind: tokenDefine, text: "=", line: line})
+			i++
+		case strings.HasPrefix(text[i:], "::="):
+			tokens = append(tokens, token{kind: tokenDefine, text: "::=", line: line})
+			i += 3
+		case c == '"':
+			// Read a double quoted string, unquoting any escape sequences.
+			j := i + 1
+			for j < len(text) && text[j] != '"' && text[j] != '\n' {
+				if text[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(text) || text[j] != '"' {
+				return nil, fmt.Errorf("line %d: unterminated string literal", line)
+			}
+			value, err := strconv.Unquote(text[i : j+1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid string literal %s: %v", line, text[i:j+1], err)
+			}
+			tokens = append(tokens, token{kind: tokenLiteral, text: value, line: line})
+			i = j + 1
+		case c == '\'':
+			// Read a single quoted string, which is taken as is.
+			j := strings.IndexByte(text[i+1:], '\'')
+			if j < 0 || strings.ContainsRune(text[i+1:i+1+j], '\n') {
+				return nil, fmt.Errorf("line %d: unterminated string literal", line)
+			}
+			tokens = append(tokens, token{kind: tokenLiteral, text: text[i+1 : i+1+j], line: line})
+			i += j + 2
+		case c == '/':
+			// Read a regular expression, where an escaped delimiter is unescaped.
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(text) && text[j] != '/' && text[j] != '\n'; j++ {
+				if text[j] == '\\' && j+1 < len(text) && text[j+1] == '/' {
+					j++
+				}
+				sb.WriteByte(text[j])
+			}
+			if j >= len(text) || text[j] != '/' {
+				return nil, fmt.Errorf("line %d: unterminated pattern", line)
+			}
+			tokens = append(tokens, token{kind: tokenPattern, text: sb.String(), line: line})
+			i = j + 1
+		case strings.IndexByte(";|()[]{}*+?", c) >= 0:
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(c), line: line})
+			i++
+		default:
+			return nil, fmt.Errorf("line %d: unexpected character %q", line, rune(c))
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, line: line}), nil
+}
+
+// parser describes the state of parsing a tokenized text grammar definition.
+type parser struct {
+	tokens   []token
+	position int
+}
+
+// peek obtains the current token without advancing the position.
+func (p *parser) peek() token {
+	return p.tokens[p.position]
+}
+
+// next obtains the current token and advances the position, unless the end of the tokens has been reached.
+func (p *parser) next() token {
+	t := p.tokens[p.position]
+	if t.kind != tokenEOF {
+		p.position++
+	}
+	return t
+}
+
+// isSymbol determines if the current token is the provided symbol.
+func (p *parser) isSymbol(symbol string) bool {
+	t := p.peek()
+	return t.kind == tokenSymbol && t.text == symbol
+}
+
+// errorf creates an error describing a problem at the provided token.
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", t.line, fmt.Sprintf(format, args...))
+}
+
+// parseAlternation parses sequences separated by '|'.
+// Returns the parsed node, or an error if parsing failed.
+func (p *parser) parseAlternation() (Node, error) {
+	// Parse our first sequence, followed by any alternatives.
+	node, err := p.parseSequence()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []Node{node}
+	for p.isSymbol("|") {
+		p.next()
+		node, err = p.parseSequence()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	// If we had no alternatives, return our sequence as is.
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return Alt(nodes...), nil
+}
+
+// parseSequence parses factors until the end of a sequence.
+// Returns the parsed node, or an error if parsing failed.
+func (p *parser) parseSequence() (Node, error) {
+	// Parse factors until we reach a token which ends our sequence.
+	var nodes []Node
+	for {
+		t := p.peek()
+		if t.kind == tokenEOF || (t.kind == tokenSymbol && strings.Contains(";|)]}", t.text)) {
+			break
+		}
+		node, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	// An empty sequence expands to an empty string, and a single factor needs no sequence.
+	if len(nodes) == 0 {
+		return Lit(""), nil
+	} else if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return Seq(nodes...), nil
+}
+
+// parseFactor parses a single element, followed by any repetition operators.
+// Returns the parsed node, or an error if parsing failed.
+func (p *parser) parseFactor() (Node, error) {
+	// Parse our element.
+	var node Node
+	t := p.next()
+	if t.kind == tokenLiteral {
+		node = Lit(t.text)
+	} else if t.kind == tokenPattern {
+		node = Pattern(t.text)
+	} else if t.kind == tokenIdent {
+		node = Ref(t.text)
+	} else if t.kind == tokenSymbol && (t.text == "(" || t.text == "[" || t.text == "{") {
+		// Parse our group's contents and its closing delimiter.
+		inner, err := p.parseAlternation()
+		if err != nil {
+			return nil, err
+		}
+		closing := map[string]string{"(": ")", "[": "]", "{": "}"}[t.text]
+		if end := p.next(); end.kind != tokenSymbol || end.text != closing {
+			return nil, p.errorf(end, "expected '%s', got %s", closing, end)
+		}
+
+		// Optional and repeated groups wrap their contents accordingly.
+		if t.text == "[" {
+			node = Opt(inner)
+		} else if t.text == "{" {
+			node = Repeat(inner, 0, -1)
+		} else {
+			node = inner
+		}
+	} else {
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+
+	// Apply any repetition operators which follow our element.
+	for {
+		if p.isSymbol("?") {
+			node = Opt(node)
+		} else if p.isSymbol("*") {
+			node = Repeat(node, 0, -1)
+		} else if p.isSymbol("+") {
+			node = Repeat(node, 1, -1)
+		} else {
+			return node, nil
+		}
+		p.next()
+	}
+}
@@ -0,0 +1,187 @@
+package go_fuzz_utils
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// defaultInterestingValues creates the default tables of interesting values for each numeric kind, such as zero,
+// boundary values, powers of two and special float values. Values are represented by their bits, as read by the
+// TypeProvider's getters: sign-extended for signed integers, and as IEEE 754 bits for floats.
+// Returns the created tables.
+func defaultInterestingValues() map[reflect.Kind][]uint64 {
+	return map[reflect.Kind][]uint64{
+		reflect.Int8:    interestingInts(8),
+		reflect.Int16:   interestingInts(16),
+		reflect.Int32:   interestingInts(32),
+		reflect.Int64:   interestingInts(64),
+		reflect.Int:     interestingInts(64),
+		reflect.Uint8:   interestingUints(8),
+		reflect.Uint16:  interestingUints(16),
+		reflect.Uint32:  interestingUints(32),
+		reflect.Uint64:  interestingUints(64),
+		reflect.Uint:    interestingUints(64),
+		reflect.Float32: interestingFloats(32),
+		reflect.Float64: interestingFloats(64),
+	}
+}
+
+// interestingInts creates a table of interesting values for a signed integer of the provided width in bits.
+func interestingInts(bits int) []uint64 {
+	// Add zero, one, negative one and the boundaries of our range.
+	minValue, maxValue := int64(-1)<<(bits-1), int64(1)<<(bits-1)-1
+	values := []int64{0, 1, -1, minValue, minValue + 1, maxValue, maxValue - 1}
+
+	// Add the powers of two around byte boundaries, and their neighbors.
+	for _, shift := range []int{7, 8, 15, 16, 31, 32} {
+		if shift < bits-1 {
+			values = append(values, 1<<shift-1, 1<<shift, 1<<shift+1, -(1 << shift), -(1<<shift)-1)
+		}
+	}
+
+	// Convert our values to their bits.
+	table := make([]uint64, len(values))
+	for i, value := range values {
+		table[i] = uint64(value)
+	}
+	return table
+}
+
+// interestingUints creates a table of interesting values for an unsigned integer of the provided width in bits.
+func interestingUints(bits int) []uint64 {
+	// Add zero, one and the boundary of our range.
+	maxValue := uint64(math.MaxUint64) >> (64 - bits)
+	table := []uint64{0, 1, maxValue, maxValue - 1}
+
+	// Add the powers of two around byte boundaries, and their neighbors.
+	for _, shift := range []int{7, 8, 15, 16, 31, 32, 63} {
+		if shift < bits {
+			table = append(table, 1<<shift-1, 1<<shift, 1<<shift+1)
+		}
+	}
+	return table
+}
+
+// interestingFloats creates a table of interesting values for a float of the provided width in bits.
+func interestingFloats(bits int) []uint64 {
+	// Determine the limits of our float type.
+	maxValue, smallestNonzero, smallestNormal := math.MaxFloat64, math.SmallestNonzeroFloat64, 0x1p-1022
+	if bits == 32 {
+		maxValue, smallestNonzero, smallestNormal = math.MaxFloat32, math.SmallestNonzeroFloat32, 0x1p-126
+	}
+
+	// Add zeros, ones, special values, and the limits of our range, including subnormals.
+	values := []float64{
+		0, math.Copysign(0, -1), 1, -1, math.NaN(), math.Inf(1), math.Inf(-1), maxValue, -maxValue,
+		smallestNonzero, -smallestNonzero, smallestNormal, -smallestNormal,
+	}
+
+	// Convert our values to their bits.
+	table := make([]uint64, len(values))
+	for i, value := range values {
+		if bits == 32 {
+			table[i] = uint64(math.Float32bits(float32(value)))
+		} else {
+			table[i] = math.Float64bits(value)
+		}
+	}
+	return table
+}
+
+// interestingBits obtains the bits representing a numeric value in the tables of interesting values.
+// Returns the bits representing the value, or false if the value's kind has no table.
+func interestingBits(v reflect.Value) (uint64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), true
+	case reflect.Float32:
+		return uint64(math.Float32bits(float32(v.Float()))), true
+	case reflect.Float64:
+		return math.Float64bits(v.Float()), true
+	default:
+		return 0, false
+	}
+}
+
+// GetParamsInterestingValueBias obtains the probability of numeric getters and Fill producing an interesting value
+// rather than reading one (represented as a float between 0 and 1).
+func (t *TypeProvider) GetParamsInterestingValueBias() float32 {
+	return t.interestingValueBias
+}
+
+// SetParamsInterestingValueBias sets the probability of numeric getters and Fill producing an interesting value rather
+// than reading one (represented as a float between 0 and 1). Interesting values are taken from a table for the kind of
+// value being produced, such as boundary values, powers of two and special float values, which can be extended using
+// AddInterestingValues. This applies to the integer and float getters without an explicit byte order, the varint
+// getters, and Fill. When Fill uses FloatModeFinite or FloatModeUnit, only the finite values or the values within
+// [0, 1) of the float tables are produced. The bias defaults to zero, in which case no decisions are made.
+// Returns an error if the bias is not within the [0,1] range.
+func (t *TypeProvider) SetParamsInterestingValueBias(bias float32) error {
+	// Validate our parameters and set them accordingly
+	if bias < 0 || bias > 1 {
+		return &InvalidParamError{Param: "bias", Reason: "bias must be between [0,1]"}
+	}
+	t.interestingValueBias = bias
+	return nil
+}
+
+// AddInterestingValues adds the provided values to the tables of interesting values used with the interesting value
+// bias. Each value is added to the table for its kind, so a uint16 value is produced by GetUint16, or when filling any
+// type with an underlying uint16 type. Values already in a table are not added again.
+// Returns an error if a value is not an integer or float.
+func (t *TypeProvider) AddInterestingValues(values ...interface{}) error {
+	// Verify all our values have a table before adding any of them.
+	for _, value := range values {
+		if _, ok := interestingBits(reflect.ValueOf(value)); !ok {
+			return &InvalidParamError{
+				Param:  "interesting value",
+				Reason: fmt.Sprintf("%v of type %T is not an integer or float", value, value),
+			}
+		}
+	}
+
+	// Add each value to the table for its kind, unless it is already in it.
+	for _, value := range values {
+		v := reflect.ValueOf(value)
+		bits, _ := interestingBits(v)
+		if indexOfInteresting(t.interestingValues[v.Kind()], bits) < 0 {
+			t.interestingValues[v.Kind()] = append(t.interestingValues[v.Kind()], bits)
+		}
+	}
+	return nil
+}
+
+// indexOfInteresting obtains the index of the provided bits in a table of interesting values.
+// Returns the index of the bits, or -1 if they are not in the table.
+func indexOfInteresting(table []uint64, bits uint64) int {
+	for i, tableBits := range table {
+		if tableBits == bits {
+			return i
+		}
+	}
+	return -1
+}
+
+// getInterestingValue determines whether an interesting value should be produced for the provided kind, given the
+// interesting value bias, and selects one from the kind's table if so. No decisions are made if the bias is zero.
+// Returns the bits of the interesting value, and a boolean indicating whether one should be produced.
+func (t *TypeProvider) getInterestingValue(kind reflect.Kind) (uint64, bool) {
+	return t.selectInterestingValue(t.interestingValues[kind])
+}
+
+// selectInterestingValue determines whether an interesting value should be produced from the provided table, given
+// the interesting value bias, and selects one from it if so. No decisions are made if the bias is zero or the table is
+// empty.
+// Returns the bits of the interesting value, and a boolean indicating whether one should be produced.
+func (t *TypeProvider) selectInterestingValue(table []uint64) (uint64, bool) {
+	// Determine if we should produce an interesting value.
+	if t.interestingValueBias <= 0 || len(table) == 0 || !t.getRandomBool(t.interestingValueBias) {
+		return 0, false
+	}
+
+	// Select a value from our table.
+	return table[t.getRandomSize(0, len(table)-1)], true
+}
@@ -0,0 +1,181 @@
+package go_fuzz_utils_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+type port uint16
+
+type interestingStruct struct {
+	I8   int8
+	I    int
+	U32  uint32
+	P    port
+	F32  float32
+	F64  float64
+	C128 complex128
+	Arr  [2]uint8
+}
+
+func TestInterestingValues(t *testing.T) {
+	// Create our type provider, which produces interesting values for every numeric value.
+	tp, err := go_fuzz_utils.NewTypeProvider([]byte{0x03, 0x04, 0x05, 0x00, 0x01, 0x02, 0x03, 0x04})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, tp.GetParamsInterestingValueBias())
+	assert.Nil(t, tp.SetParamsInterestingValueBias(1))
+	assert.EqualValues(t, 1, tp.GetParamsInterestingValueBias())
+
+	// Each value consumes a single byte to select its index within the table for its kind.
+	i8, err := tp.GetInt8()
+	assert.Nil(t, err)
+	assert.EqualValues(t, math.MinInt8, i8)
+	f64, err := tp.GetFloat64()
+	assert.Nil(t, err)
+	assert.True(t, math.IsNaN(f64))
+	f32, err := tp.GetFloat32()
+	assert.Nil(t, err)
+	assert.True(t, math.IsInf(float64(f32), 1))
+	u64, err := tp.GetUint64()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, u64)
+	i64, err := tp.GetVarint()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, i64)
+	i16, err := tp.GetInt16()
+	assert.Nil(t, err)
+	assert.EqualValues(t, -1, i16)
+
+	// Getters with an explicit byte order are unaffected.
+	u16, err := tp.GetUint16BE()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x0304, u16)
+
+	// Add our own interesting values and verify they're produced for any type of the same kind.
+	tp, err = go_fuzz_utils.NewTypeProvider([]byte{13}) // the index of our value in the uint16 table
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsInterestingValueBias(1))
+	assert.Nil(t, tp.AddInterestingValues(port(8080)))
+	var p port
+	assert.Nil(t, tp.Fill(&p))
+	assert.EqualValues(t, 8080, p)
+
+	// Invalid parameters return an error.
+	err = tp.SetParamsInterestingValueBias(1.5)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+	err = tp.AddInterestingValues(1, "string")
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+}
+
+func TestInterestingValuesFill(t *testing.T) {
+	// Create our type provider, which produces interesting values half of the time.
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsInterestingValueBias(0.5))
+
+	// Fill a number of values and verify boundary values are produced.
+	foundBoundary, foundNaN := false, false
+	for i := 0; i < 100; i++ {
+		var v interestingStruct
+		assert.Nil(t, tp.Fill(&v))
+		foundBoundary = foundBoundary || v.I == math.MinInt64 || v.I == math.MaxInt64 || v.I == -1
+		foundNaN = foundNaN || math.IsNaN(v.F64)
+	}
+	assert.True(t, foundBoundary)
+	assert.True(t, foundNaN)
+}
+
+func TestEncodeInterestingValues(t *testing.T) {
+	modes := []go_fuzz_utils.DecisionMode{go_fuzz_utils.DecisionModeData, go_fuzz_utils.DecisionModeTail}
+	for _, mode := range modes {
+		t.Run(mode.String(), func(t *testing.T) {
+			// Create a value with both interesting and other values.
+			value := interestingStruct{
+				I8: 7, I: math.MinInt64, U32: 1 << 16, P: 8080, F32: 1.5, F64: math.Inf(-1),
+				C128: complex(math.SmallestNonzeroFloat64, 2.5), Arr: [2]uint8{255, 3},
+			}
+
+			// Encode our value with our custom interesting values, and fill a new value from the encoded data.
+			for _, varint := range []bool{false, true} {
+				tp, err := go_fuzz_utils.NewTypeProvider(nil)
+				assert.Nil(t, err)
+				assert.Nil(t, tp.SetParamsDecisionMode(mode))
+				assert.Nil(t, tp.SetParamsInterestingValueBias(0.5))
+				assert.Nil(t, tp.AddInterestingValues(port(8080)))
+				tp.SetParamsVarintIntegers(varint)
+				data, err := tp.Encode(&value)
+				assert.Nil(t, err)
+
+				tp, err = go_fuzz_utils.NewTypeProvider(data)
+				assert.Nil(t, err)
+				assert.Nil(t, tp.SetParamsDecisionMode(mode))
+				assert.Nil(t, tp.SetParamsInterestingValueBias(0.5))
+				assert.Nil(t, tp.AddInterestingValues(port(8080)))
+				tp.SetParamsVarintIntegers(varint)
+				var filled interestingStruct
+				assert.Nil(t, tp.Fill(&filled))
+				assert.EqualValues(t, value, filled)
+			}
+
+			// Values which are not interesting cannot be encoded if every value must be interesting.
+			tp, err := go_fuzz_utils.NewTypeProvider(nil)
+			assert.Nil(t, err)
+			assert.Nil(t, tp.SetParamsDecisionMode(mode))
+			assert.Nil(t, tp.SetParamsInterestingValueBias(1))
+			_, err = tp.Encode(&value)
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestInterestingValuesFloatModes(t *testing.T) {
+	modes := []go_fuzz_utils.FloatMode{go_fuzz_utils.FloatModeFinite, go_fuzz_utils.FloatModeUnit}
+	for _, mode := range modes {
+		t.Run(mode.String(), func(t *testing.T) {
+			// Create our type provider, which always produces interesting values which fit our float mode.
+			tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+			assert.Nil(t, err)
+			assert.Nil(t, tp.SetParamsFloatMode(mode))
+			assert.Nil(t, tp.SetParamsInterestingValueBias(1))
+
+			// Every float should be an interesting value which can be produced in our float mode.
+			for i := 0; i < 20; i++ {
+				var f32 float32
+				var f64 float64
+				assert.Nil(t, tp.Fill(&f32))
+				assert.Nil(t, tp.Fill(&f64))
+				for _, f := range []float64{float64(f32), f64} {
+					assert.False(t, math.IsNaN(f) || math.IsInf(f, 0))
+					if mode == go_fuzz_utils.FloatModeUnit {
+						assert.True(t, f >= 0 && f < 1)
+					}
+				}
+			}
+
+			// Interesting values should be encoded, and values which are not cannot be encoded.
+			tp, err = go_fuzz_utils.NewTypeProvider(nil)
+			assert.Nil(t, err)
+			assert.Nil(t, tp.SetParamsFloatMode(mode))
+			assert.Nil(t, tp.SetParamsInterestingValueBias(1))
+			value := [2]float64{0, math.SmallestNonzeroFloat64}
+			if mode == go_fuzz_utils.FloatModeFinite {
+				value[1] = math.MaxFloat64
+			}
+			data, err := tp.Encode(&value)
+			assert.Nil(t, err)
+			_, err = tp.Encode(&[1]float64{0.375})
+			assert.NotNil(t, err)
+			tp, err = go_fuzz_utils.NewTypeProvider(data)
+			assert.Nil(t, err)
+			assert.Nil(t, tp.SetParamsFloatMode(mode))
+			assert.Nil(t, tp.SetParamsInterestingValueBias(1))
+			var filled [2]float64
+			assert.Nil(t, tp.Fill(&filled))
+			assert.EqualValues(t, value, filled)
+		})
+	}
+}
@@ -0,0 +1,430 @@
+package go_fuzz_utils
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// maxMutationDelta describes the largest amount a numeric value is incremented or decremented by when mutated.
+const maxMutationDelta = 16
+
+// mutation describes a small mutation which changes an element of a string or container.
+type mutation int
+
+const (
+	// mutationInsert describes inserting an element.
+	mutationInsert mutation = iota
+	// mutationDelete describes deleting an element.
+	mutationDelete
+	// mutationReplace describes replacing an element, which does not change the length.
+	mutationReplace
+)
+
+// mutator mutates existing values in place using the data of a TypeProvider. It walks values in the same way as
+// fillValue, deciding for each value whether to keep it, replace it or mutate it slightly.
+type mutator struct {
+	// t represents the TypeProvider the data and parameters used to mutate values are obtained from.
+	t *TypeProvider
+	// visited represents the pointers, slices and maps walked so far, so values which refer to themselves are only
+	// mutated once.
+	visited referenceSet
+	// walker represents the walker used to mutate the fields and elements of values, in the same way Fill fills them.
+	walker *walker
+}
+
+// GetParamsMutateBiases obtains the probabilities of a value being replaced, or otherwise slightly mutated, when using
+// Mutate (represented as floats between 0 and 1).
+func (t *TypeProvider) GetParamsMutateBiases() (float32, float32) {
+	return t.mutateReplaceBias, t.mutateBias
+}
+
+// SetParamsMutateBiases sets the probabilities of a value being replaced, or otherwise slightly mutated, when using
+// Mutate (represented as floats between 0 and 1). Values which are neither replaced nor mutated are kept as they are.
+// The biases default to 0.05 and 0.1.
+// Returns an error if either bias is not within the [0,1] range.
+func (t *TypeProvider) SetParamsMutateBiases(replaceBias float32, mutateBias float32) error {
+	// Validate our parameters and set them accordingly
+	if replaceBias < 0 || replaceBias > 1 || mutateBias < 0 || mutateBias > 1 {
+		return &InvalidParamError{Param: "bias", Reason: "bias must be between [0,1]"}
+	}
+	t.mutateReplaceBias = replaceBias
+	t.mutateBias = mutateBias
+	return nil
+}
+
+// Mutate perturbs an existing value at a provided pointer in place, such as a known-valid baseline object. The value
+// is walked in the same way as Fill, following the same field tags and depth limit, and for each value the data
+// decides whether to keep it, replace it with a value populated as Fill would, or apply a small mutation to it:
+// booleans are negated, numbers have a bit flipped or a small delta added or subtracted, strings and byte slices have
+// a character inserted, deleted or replaced, and slices and maps have an element inserted or deleted, within their
+// length bounds. Structs, arrays, pointers and interfaces are walked rather than mutated, while channels, functions,
+// strings with a pattern and values populated by custom fill methods can only be kept or replaced. Numbers mutated
+// outside of a range set by their field tag are kept as they are. Values beyond the depth limit, and uintptr values,
+// which Fill does not populate, are always kept. Map values are walked in the order of their keys, which is only
+// reproducible across processes if the keys do not contain pointers or channels. Values whose keys can't be looked
+// up, such as NaN, are always kept.
+// Slices, maps and values at pointers are mutated in place, so values they share with the baseline are also
+// mutated. Callers which reuse a baseline should provide a deep copy of it.
+// Returns an error if one is encountered.
+func (t *TypeProvider) Mutate(i interface{}) error {
+	// We should have been provided a pointer, so we obtain reflect pkg values and dereference.
+	v := reflect.Indirect(reflect.ValueOf(i))
+
+	// Next we mutate the value, with a new set of values which may be reused by replaced values.
+	m := &mutator{t: t, visited: make(referenceSet)}
+	m.walker = &walker{t: t, visit: m.mutateValue, setCopies: true}
+	return t.withAliases(func() error {
+		return m.mutateValue(v, 0, nil)
+	})
+}
+
+// mutateValue keeps, replaces or mutates a value based on reflection, walking structures and containers recursively
+// in the same way as fillValue. If the value is a struct field with a `fuzz` tag, the provided constraints override
+// the TypeProvider's parameters for it, otherwise they are nil.
+// Returns an error if one is encountered.
+func (m *mutator) mutateValue(v reflect.Value, currentDepth int, constraints *fieldConstraints) error {
+	// If we can't set the value, or it's beyond our depth limit, we keep it as it is.
+	if !v.CanSet() || m.t.depthExceeded(v.Type(), currentDepth) {
+		return nil
+	}
+
+	// Determine if we should replace our value, populating it as Fill would.
+	if m.t.getRandomBool(m.t.mutateReplaceBias) {
+		return m.t.fillValue(v, currentDepth, constraints)
+	}
+
+	// Values populated by custom fill methods, channels and functions can only be kept or replaced.
+	if m.t.hasCustomFill(v) || v.Kind() == reflect.Chan || v.Kind() == reflect.Func {
+		return nil
+	}
+
+	// Determine how to mutate our value based on its type.
+	if v.Kind() == reflect.Bool {
+		if m.t.getRandomBool(m.t.mutateBias) {
+			v.SetBool(!v.Bool())
+		}
+	} else if v.CanInt() || (v.CanUint() && v.Kind() != reflect.Uintptr) || v.CanFloat() || v.CanComplex() {
+		if m.t.getRandomBool(m.t.mutateBias) {
+			m.mutateNumber(v, constraints)
+		}
+	} else if v.Kind() == reflect.String {
+		// Strings with a pattern constraint can only be kept or replaced.
+		if constraints.getPattern() == nil && m.t.getRandomBool(m.t.mutateBias) {
+			return m.mutateString(v, constraints)
+		}
+	} else if v.Kind() == reflect.Slice {
+		// If this slice refers to itself, we've already walked it.
+		if !v.IsNil() && m.visited.visit(v) {
+			return nil
+		}
+
+		// Determine if we should insert or delete an element. Byte slices are mutated as a whole, as they're very common
+		// and walking each of their elements would take too long.
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if m.t.getRandomBool(m.t.mutateBias) {
+				return m.mutateBytes(v, constraints)
+			}
+			return nil
+		} else if m.t.getRandomBool(m.t.mutateBias) {
+			err := m.mutateSliceLength(v, currentDepth, constraints)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Walk each of our elements.
+		return m.walker.walkElements(v, currentDepth)
+	} else if v.Kind() == reflect.Map {
+		// If this map refers to itself, we've already walked it.
+		if !v.IsNil() && m.visited.visit(v) {
+			return nil
+		}
+
+		// Determine if we should insert or delete an element.
+		if m.t.getRandomBool(m.t.mutateBias) {
+			err := m.mutateMapLength(v, currentDepth, constraints)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Walk each of our values in a consistent order. Map values can't be set directly, so we mutate a copy of each
+		// of them and set it in our map. Values whose keys can't be looked up, such as NaN, can't be set, so we keep them.
+		return m.walker.walkMap(v, currentDepth)
+	} else if v.Kind() == reflect.Ptr {
+		// Nil pointers can only be kept or replaced, and pointers which refer to themselves are only walked once.
+		if v.IsNil() || m.visited.visit(v) {
+			return nil
+		}
+		return m.walker.walkPointer(v, currentDepth, constraints)
+	} else if v.Kind() == reflect.Interface {
+		// Nil interfaces can only be kept or replaced.
+		if v.IsNil() {
+			return nil
+		}
+
+		// The value held by an interface can't be set directly, so we mutate a copy of it and assign it to our interface.
+		return m.walker.walkInterface(v, currentDepth, constraints)
+	} else if v.Kind() == reflect.Array {
+		// Walk each of our elements.
+		return m.walker.walkElements(v, currentDepth)
+	} else if v.Kind() == reflect.Struct {
+		// For structs we need to recursively walk every field Fill would populate.
+		return m.walker.walkStruct(v, currentDepth)
+	}
+
+	// Channels, functions and unknown value types are kept as they are.
+	return nil
+}
+
+// mutateNumber applies a small mutation to an integer, float or complex value: flipping one of its bits, or adding or
+// subtracting a small delta. Integers wrap around on overflow. If the value has a range constraint and the mutated
+// value is outside of it, the value is kept as it is.
+func (m *mutator) mutateNumber(v reflect.Value, constraints *fieldConstraints) {
+	// Mutate a copy of our value, depending on its kind.
+	mutated := reflect.New(v.Type()).Elem()
+	mutated.Set(v)
+	if v.CanInt() {
+		mutated.SetInt(int64(m.mutateBits(uint64(v.Int()), v.Type().Bits())))
+	} else if v.CanUint() {
+		mutated.SetUint(m.mutateBits(v.Uint(), v.Type().Bits()))
+	} else if v.CanFloat() {
+		mutated.SetFloat(m.mutateFloat(v.Float(), v.Type().Bits()))
+	} else {
+		// Complex values have either their real or imaginary part mutated.
+		c := v.Complex()
+		if m.t.getRandomBool(0.5) {
+			c = complex(m.mutateFloat(real(c), v.Type().Bits()/2), imag(c))
+		} else {
+			c = complex(real(c), m.mutateFloat(imag(c), v.Type().Bits()/2))
+		}
+		mutated.SetComplex(c)
+	}
+
+	// If our mutated value satisfies our constraints, set it.
+	if constraints.encodeValue(mutated) == nil {
+		v.Set(mutated)
+	}
+}
+
+// mutateBits applies a small mutation to an integer of the provided width in bits: flipping one of its bits, or adding
+// or subtracting a small delta.
+// Returns the mutated integer.
+func (m *mutator) mutateBits(x uint64, bits int) uint64 {
+	switch m.t.getRandomSize(0, 2) {
+	case 0:
+		return x ^ (1 << m.t.getRandomSize(0, bits-1))
+	case 1:
+		return x + uint64(m.t.getRandomSize(1, maxMutationDelta))
+	default:
+		return x - uint64(m.t.getRandomSize(1, maxMutationDelta))
+	}
+}
+
+// mutateFloat applies a small mutation to a float of the provided width in bits: flipping one of its bits, or adding
+// or subtracting a small delta.
+// Returns the mutated float.
+func (m *mutator) mutateFloat(f float64, bits int) float64 {
+	switch m.t.getRandomSize(0, 2) {
+	case 0:
+		// Flip a bit in the representation of our float at its width.
+		bit := m.t.getRandomSize(0, bits-1)
+		if bits == 32 {
+			return float64(math.Float32frombits(math.Float32bits(float32(f)) ^ (1 << bit)))
+		}
+		return math.Float64frombits(math.Float64bits(f) ^ (1 << bit))
+	case 1:
+		return f + float64(m.t.getRandomSize(1, maxMutationDelta))
+	default:
+		return f - float64(m.t.getRandomSize(1, maxMutationDelta))
+	}
+}
+
+// mutateString applies a small mutation to a string: inserting, deleting or replacing a character, where characters
+// are bytes for raw strings or runes otherwise. New characters are read according to the string mode. If the mutated
+// string is not within the length bounds, the string is kept as it is.
+// Returns an error if the end of stream has been reached.
+func (m *mutator) mutateString(v reflect.Value, constraints *fieldConstraints) error {
+	// Split our string into characters.
+	var chars []string
+	s := v.String()
+	if m.t.stringMode == StringModeRaw {
+		for i := 0; i < len(s); i++ {
+			chars = append(chars, s[i:i+1])
+		}
+	} else {
+		for _, r := range s {
+			chars = append(chars, string(r))
+		}
+	}
+
+	// Determine which of our mutations keep our string within our bounds.
+	minLength, maxLength := constraints.getLengthBounds(m.t.stringMinLength, m.t.stringMaxLength)
+	length := m.t.tokenLength([]byte(s), true)
+	ops := m.lengthMutations(len(chars), length, minLength, maxLength)
+	if len(ops) == 0 {
+		return nil
+	}
+
+	// Apply one of our mutations.
+	op := ops[m.t.getRandomSize(0, len(ops)-1)]
+	if op == mutationDelete {
+		i := m.t.getRandomSize(0, len(chars)-1)
+		chars = append(chars[:i], chars[i+1:]...)
+	} else if op == mutationReplace {
+		i := m.t.getRandomSize(0, len(chars)-1)
+		c, err := m.t.readString(1)
+		if err != nil {
+			return err
+		}
+		chars[i] = c
+	} else {
+		i := m.t.getRandomSize(0, len(chars))
+		c, err := m.t.readString(1)
+		if err != nil {
+			return err
+		}
+		chars = append(chars[:i], append([]string{c}, chars[i:]...)...)
+	}
+
+	// If our mutated string is within our bounds, set it.
+	mutated := strings.Join(chars, "")
+	if length = m.t.tokenLength([]byte(mutated), true); length >= minLength && length <= maxLength {
+		v.SetString(mutated)
+	}
+	return nil
+}
+
+// mutateBytes applies a small mutation to a byte slice: inserting or deleting a byte, or mutating a byte as done by
+// mutateBits. The mutated bytes are set as a new slice, so the array underlying the original slice is not modified.
+// Returns an error if the end of stream has been reached.
+func (m *mutator) mutateBytes(v reflect.Value, constraints *fieldConstraints) error {
+	// Determine which of our mutations keep our slice within our bounds.
+	b := v.Bytes()
+	minLength, maxLength := constraints.getLengthBounds(m.t.sliceMinSize, m.t.sliceMaxSize)
+	ops := m.lengthMutations(len(b), len(b), minLength, maxLength)
+	if len(ops) == 0 {
+		return nil
+	}
+
+	// Apply one of our mutations to a copy of our bytes.
+	mutated := make([]byte, 0, len(b)+1)
+	op := ops[m.t.getRandomSize(0, len(ops)-1)]
+	if op == mutationDelete {
+		i := m.t.getRandomSize(0, len(b)-1)
+		mutated = append(append(mutated, b[:i]...), b[i+1:]...)
+	} else if op == mutationReplace {
+		i := m.t.getRandomSize(0, len(b)-1)
+		mutated = append(mutated, b...)
+		mutated[i] = byte(m.mutateBits(uint64(mutated[i]), 8))
+	} else {
+		i := m.t.getRandomSize(0, len(b))
+		x, err := m.t.GetByte()
+		if err != nil {
+			return err
+		}
+		mutated = append(append(append(mutated, b[:i]...), x), b[i:]...)
+	}
+
+	// Set our mutated bytes. This keeps the type of our slice, even if its element type is a named byte type.
+	v.SetBytes(mutated)
+	return nil
+}
+
+// mutateSliceLength inserts a newly populated element into a slice, or deletes one of its elements, within its length
+// bounds. The elements are set as a new slice, so the array underlying the original slice is not modified.
+// Returns an error if one is encountered.
+func (m *mutator) mutateSliceLength(v reflect.Value, currentDepth int, constraints *fieldConstraints) error {
+	// Determine which of our mutations keep our slice within our bounds.
+	minLength, maxLength := constraints.getLengthBounds(m.t.sliceMinSize, m.t.sliceMaxSize)
+	ops := m.lengthMutations(0, v.Len(), minLength, maxLength)
+	if len(ops) == 0 {
+		return nil
+	}
+
+	// Create our new slice, copying our elements around the one we delete, or the one we insert and populate as Fill
+	// would.
+	if ops[m.t.getRandomSize(0, len(ops)-1)] == mutationDelete {
+		length := v.Len() - 1
+		slice := reflect.MakeSlice(v.Type(), length, length)
+		i := m.t.getRandomSize(0, length)
+		reflect.Copy(slice, v.Slice(0, i))
+		reflect.Copy(slice.Slice(i, length), v.Slice(i+1, v.Len()))
+		v.Set(slice)
+	} else {
+		length := v.Len() + 1
+		slice := reflect.MakeSlice(v.Type(), length, length)
+		i := m.t.getRandomSize(0, length-1)
+		reflect.Copy(slice, v.Slice(0, i))
+		reflect.Copy(slice.Slice(i+1, length), v.Slice(i, v.Len()))
+		err := m.t.fillValue(slice.Index(i), m.t.elemDepth(v.Type(), currentDepth), nil)
+		if err != nil {
+			return wrapFillError(err, fmt.Sprintf("[%d]", i))
+		}
+		v.Set(slice)
+	}
+	return nil
+}
+
+// mutateMapLength inserts a newly populated key-value pair into a map, or deletes one of its keys, within its length
+// bounds.
+// Returns an error if one is encountered.
+func (m *mutator) mutateMapLength(v reflect.Value, currentDepth int, constraints *fieldConstraints) error {
+	// Determine which of our mutations keep our map within our bounds.
+	minLength, maxLength := constraints.getLengthBounds(m.t.mapMinSize, m.t.mapMaxSize)
+	ops := m.lengthMutations(0, v.Len(), minLength, maxLength)
+	if len(ops) == 0 {
+		return nil
+	}
+
+	// If we're deleting a key, select one which can be looked up in a consistent order.
+	if ops[m.t.getRandomSize(0, len(ops)-1)] == mutationDelete {
+		var keys []reflect.Value
+		for _, entry := range sortedMapEntries(v) {
+			if entry.settable {
+				keys = append(keys, entry.key)
+			}
+		}
+		if len(keys) > 0 {
+			v.SetMapIndex(keys[m.t.getRandomSize(0, len(keys)-1)], reflect.Value{})
+		}
+		return nil
+	}
+
+	// Otherwise populate a new key-value pair as Fill would, creating our map if it is nil.
+	mKey := reflect.New(v.Type().Key()).Elem()
+	mValue := reflect.New(v.Type().Elem()).Elem()
+	err := m.t.fillValue(mKey, m.t.elemDepth(v.Type(), currentDepth), nil)
+	if err != nil {
+		return wrapFillError(err, "[key]")
+	}
+	err = m.t.fillValue(mValue, m.t.elemDepth(v.Type(), currentDepth), nil)
+	if err != nil {
+		return wrapFillError(err, "[value]")
+	}
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+	v.SetMapIndex(mKey, mValue)
+	return nil
+}
+
+// lengthMutations determines the mutations which may be applied to a value with the provided length, given its length
+// bounds. Values with replaceable elements may have one of them replaced, and elements may be inserted or deleted if
+// the length remains within the bounds.
+// Returns the mutations which may be applied.
+func (m *mutator) lengthMutations(replaceable int, length int, minLength int, maxLength int) []mutation {
+	var ops []mutation
+	if length < maxLength {
+		ops = append(ops, mutationInsert)
+	}
+	if length > minLength && length > 0 {
+		ops = append(ops, mutationDelete)
+	}
+	if replaceable > 0 {
+		ops = append(ops, mutationReplace)
+	}
+	return ops
+}
@@ -0,0 +1,236 @@
+package go_fuzz_utils_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+// mutateBaseline describes a struct used as a baseline value to mutate.
+type mutateBaseline struct {
+	B     bool
+	I8    int8
+	U16   uint16
+	F32   float32
+	C128  complex128
+	Fixed uint8 `fuzz:"min=10,max=10"`
+	S     string
+	Bytes []byte
+	Ints  []int32
+	M     map[string]uint8
+	Ptr   *linkedNode
+	Never uint8 `fuzz:"-"`
+}
+
+// newMutateBaseline creates a new baseline value to mutate.
+func newMutateBaseline() mutateBaseline {
+	return mutateBaseline{
+		B:     true,
+		I8:    -5,
+		U16:   0x1234,
+		F32:   1.5,
+		C128:  complex(2, 3),
+		Fixed: 10,
+		S:     "hello",
+		Bytes: []byte{1, 2, 3},
+		Ints:  []int32{7, 8, 9},
+		M:     map[string]uint8{"a": 1, "b": 2, "c": 3},
+		Ptr:   &linkedNode{Value: 4},
+		Never: 0x77,
+	}
+}
+
+func TestMutateKeep(t *testing.T) {
+	// Create a type provider with no data, which keeps every value.
+	tp, err := go_fuzz_utils.NewTypeProvider(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsMutateBiases(0, 0))
+
+	// Mutating our value should not consume any data or change it.
+	v := newMutateBaseline()
+	assert.Nil(t, tp.Mutate(&v))
+	assert.EqualValues(t, newMutateBaseline(), v)
+}
+
+func TestMutateReplace(t *testing.T) {
+	// Create a type provider which replaces every value, and one to fill a value from the same data.
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+	assert.Nil(t, err)
+	replaceBias, mutateBias := tp.GetParamsMutateBiases()
+	assert.EqualValues(t, 0.05, replaceBias)
+	assert.EqualValues(t, 0.1, mutateBias)
+	assert.Nil(t, tp.SetParamsMutateBiases(1, 0))
+	tpFill, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+	assert.Nil(t, err)
+
+	// Replacing our value should populate it as Fill would.
+	v := newMutateBaseline()
+	assert.Nil(t, tp.Mutate(&v))
+	filled := mutateBaseline{Never: 0x77}
+	assert.Nil(t, tpFill.Fill(&filled))
+	assert.EqualValues(t, filled, v)
+}
+
+func TestMutateSmall(t *testing.T) {
+	// Create a type provider which applies a small mutation to every value.
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsMutateBiases(0, 1))
+	assert.Nil(t, tp.SetParamsStringBounds(0, 10))
+	assert.Nil(t, tp.SetParamsSliceBounds(0, 10))
+	assert.Nil(t, tp.SetParamsMapBounds(0, 10))
+
+	// Mutate our value, keeping a reference to the original byte slice.
+	v := newMutateBaseline()
+	original := newMutateBaseline()
+	bytes := v.Bytes
+	assert.Nil(t, tp.Mutate(&v))
+
+	// Booleans and numbers should always change, unless they're constrained to a single value.
+	assert.False(t, v.B)
+	assert.NotEqualValues(t, original.I8, v.I8)
+	assert.NotEqualValues(t, original.U16, v.U16)
+	assert.NotEqualValues(t, original.F32, v.F32)
+	assert.NotEqualValues(t, original.C128, v.C128)
+	assert.NotEqualValues(t, original.Ptr.Value, v.Ptr.Value)
+	assert.EqualValues(t, 10, v.Fixed)
+	assert.EqualValues(t, 0x77, v.Never)
+
+	// Strings and containers should have at most one element inserted or deleted.
+	assert.InDelta(t, len(original.S), len(v.S), 1)
+	assert.InDelta(t, len(original.Bytes), len(v.Bytes), 1)
+	assert.InDelta(t, len(original.Ints), len(v.Ints), 1)
+	assert.InDelta(t, len(original.M), len(v.M), 1)
+	assert.EqualValues(t, original.Bytes, bytes)
+}
+
+func TestMutateBounds(t *testing.T) {
+	// Create a type provider which applies a small mutation to every value, with fixed lengths.
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsMutateBiases(0, 1))
+	assert.Nil(t, tp.SetParamsSliceBounds(3, 3))
+	assert.Nil(t, tp.SetParamsMapBounds(3, 3))
+
+	// Lengths should remain within their bounds.
+	for i := 0; i < 10; i++ {
+		v := newMutateBaseline()
+		assert.Nil(t, tp.Mutate(&v))
+		assert.Len(t, v.Bytes, 3)
+		assert.Len(t, v.Ints, 3)
+		assert.Len(t, v.M, 3)
+	}
+}
+
+func TestMutateDeterministic(t *testing.T) {
+	// Mutating the same value with the same data should produce the same result, regardless of map ordering.
+	var results []mutateBaseline
+	for i := 0; i < 5; i++ {
+		tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+		assert.Nil(t, err)
+		assert.Nil(t, tp.SetParamsMutateBiases(0.25, 0.5))
+		v := newMutateBaseline()
+		assert.Nil(t, tp.Mutate(&v))
+		results = append(results, v)
+	}
+	for _, result := range results[1:] {
+		assert.EqualValues(t, results[0].M, result.M)
+		assert.EqualValues(t, results[0].Ints, result.Ints)
+	}
+}
+
+func TestMutateCyclesAndDepth(t *testing.T) {
+	// Create a type provider which applies a small mutation to every value.
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsMutateBiases(0, 1))
+
+	// Values which refer to themselves should only be mutated once.
+	node := &linkedNode{Value: 1}
+	node.Next = node
+	assert.Nil(t, tp.Mutate(&node))
+	assert.True(t, node.Next == node)
+	assert.NotEqualValues(t, 1, node.Value)
+
+	// Values beyond the depth limit should be kept.
+	assert.Nil(t, tp.SetParamsDepthLimit(2))
+	chain := &linkedNode{Value: 1, Next: &linkedNode{Value: 2, Next: &linkedNode{Value: 3}}}
+	assert.Nil(t, tp.Mutate(&chain))
+	assert.NotEqualValues(t, 1, chain.Value)
+	assert.NotEqualValues(t, 2, chain.Next.Value)
+	assert.EqualValues(t, 3, chain.Next.Next.Value)
+
+	// Invalid biases return an error.
+	assert.True(t, errors.Is(tp.SetParamsMutateBiases(-1, 0), go_fuzz_utils.ErrInvalidParam))
+	assert.True(t, errors.Is(tp.SetParamsMutateBiases(0, 2), go_fuzz_utils.ErrInvalidParam))
+}
+
+func TestMutateMapKeys(t *testing.T) {
+	// Maps with NaN keys should be walked without panicking, keeping the values of NaN keys as they are.
+	for _, biases := range [][2]float32{{0, 0}, {0, 1}, {0.5, 0.5}} {
+		tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+		assert.Nil(t, err)
+		assert.Nil(t, tp.SetParamsMutateBiases(biases[0], biases[1]))
+		m := map[float64]int{math.NaN(): 1, 2: 3}
+		assert.Nil(t, tp.Mutate(&m))
+		for k, v := range m {
+			if math.IsNaN(k) && biases[0] == 0 {
+				assert.EqualValues(t, 1, v)
+			}
+		}
+	}
+
+	// Maps should be walked in the order of their keys, regardless of where they're stored or inserted.
+	type key struct {
+		Name string
+		ID   int
+	}
+	var results []map[key]uint8
+	for i := 0; i < 10; i++ {
+		m := make(map[key]uint8)
+		for j := 0; j < 8; j++ {
+			k := (i + j) % 8
+			m[key{Name: string(rune('a' + k)), ID: k}] = uint8(k)
+		}
+		tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+		assert.Nil(t, err)
+		assert.Nil(t, tp.SetParamsMutateBiases(0, 0.5))
+		assert.Nil(t, tp.Mutate(&m))
+		results = append(results, m)
+	}
+	for _, result := range results[1:] {
+		assert.EqualValues(t, results[0], result)
+	}
+}
+
+func TestMutateUintptr(t *testing.T) {
+	// Values which Fill does not populate should be kept.
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsMutateBiases(0, 1))
+	v := struct {
+		P uintptr
+		U uint8
+	}{P: 0x1000, U: 1}
+	assert.Nil(t, tp.Mutate(&v))
+	assert.EqualValues(t, 0x1000, v.P)
+	assert.NotEqualValues(t, 1, v.U)
+}
+
+// mutateByte describes a named byte type, used as the element type of a byte slice to mutate.
+type mutateByte uint8
+
+func TestMutateNamedBytes(t *testing.T) {
+	// Byte slices with a named element type should be mutated without changing their type.
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsMutateBiases(0, 1))
+	v := struct {
+		B []mutateByte
+	}{B: []mutateByte{1, 2, 3}}
+	assert.Nil(t, tp.Mutate(&v))
+	assert.NotEqualValues(t, []mutateByte{1, 2, 3}, v.B)
+}
@@ -0,0 +1,21 @@
+package go_fuzz_utils
+
+import "fmt"
+
+// ParamsString obtains a human-readable description of the fill parameters of this TypeProvider, such as for logging
+// them when a test fails, as they're needed to reproduce the values filled from its data.
+func (t *TypeProvider) ParamsString() string {
+	return fmt.Sprintf("decision mode: %v, exhaustion policy: %v, byte order: %v, string mode: %v, "+
+		"string count runes: %v, string bounds: [%d, %d], slice bounds: [%d, %d], map bounds: [%d, %d], "+
+		"channel bounds: [%d, %d], nil biases (map/ptr/slice/channel/func): %v/%v/%v/%v/%v, "+
+		"channel close bias: %v, func error bias: %v, skip field bias: %v, interesting value bias: %v, float mode: %v, "+
+		"float special bias: %v, dictionary tokens: %d, dictionary bias: %v, depth limit: %d, "+
+		"depth counts containers: %v, alias bias: %v, mutate biases (replace/mutate): %v/%v, varint integers: %v, "+
+		"fill unexported fields: %v",
+		t.decisionMode, t.exhaustionPolicy, t.byteOrder, t.stringMode, t.stringCountRunes, t.stringMinLength,
+		t.stringMaxLength, t.sliceMinSize, t.sliceMaxSize, t.mapMinSize, t.mapMaxSize, t.chanMinSize, t.chanMaxSize,
+		t.mapNilBias, t.ptrNilBias, t.sliceNilBias, t.chanNilBias, t.funcNilBias, t.chanCloseBias, t.funcErrorBias,
+		t.skipFieldBias, t.interestingValueBias, t.floatMode, t.floatSpecialBias, len(t.dictionary), t.dictionaryBias,
+		t.depthLimit, t.depthCountContainers, t.aliasBias, t.mutateReplaceBias, t.mutateBias, t.varintIntegers,
+		t.fillUnexportedFields)
+}
@@ -0,0 +1,163 @@
+package go_fuzz_utils
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// GetUint64InRange obtains an uint64 within the inclusive range [min, max] from the current position in the buffer.
+// This advances the position by the minimum number of bytes needed to represent max - min, plus a byte of slack which
+// keeps every value in the range close to equally likely. No bytes are read if the bounds are equal, and 8 bytes are
+// read if the range covers every uint64.
+// Returns the read uint64, or an error if the range is invalid or the end of stream has been reached.
+func (t *TypeProvider) GetUint64InRange(min uint64, max uint64) (uint64, error) {
+	// Validate our parameters
+	if max < min {
+		return 0, &InvalidParamError{Param: "range", Reason: fmt.Sprintf("min: %d, max: %d", min, max)}
+	}
+
+	// Obtain the data to back our value
+	span := max - min
+	b, err := t.getFixedBytes(rangeWidth(span))
+	if err != nil {
+		return 0, err
+	}
+
+	// If our range covers every value, our bytes are used as they are. Otherwise, we construct our value from our bytes
+	// using our byte order, followed by our byte of slack, and scale it into our range before offsetting it by our
+	// minimum.
+	if span == math.MaxUint64 {
+		return t.uintFromBytes(b), nil
+	} else if span == 0 {
+		return min, nil
+	}
+	return min + scaleToRange(t.uintFromBytes(b[:len(b)-1]), b[len(b)-1], span), nil
+}
+
+// rangeWidth obtains the number of bytes read by GetUint64InRange for a range spanning [0, span].
+func rangeWidth(span uint64) int {
+	if span == math.MaxUint64 {
+		return 8
+	} else if span == 0 {
+		return 0
+	}
+	return decisionWidth(span) + 1
+}
+
+// scaleToRange maps a value read for a range spanning [0, span] onto that range. The value is made of x, which holds
+// as many bytes as span needs, followed by a byte of slack. It is treated as a fraction of all values of its size and
+// multiplied by the number of values in the range, so each value in the range is produced by either n or n+1 inputs,
+// where n is at least 256. This must not be used for a span of zero, or one covering every uint64.
+// Returns the value in the range [0, span].
+func scaleToRange(x uint64, slack byte, span uint64) uint64 {
+	// Compute x * (span + 1) + slack * (span + 1) / 256 as a 128-bit value. The fraction dropped by the division can't
+	// change the result of the shift below, as it only drops whole values.
+	n := span + 1
+	hi, lo := bits.Mul64(x, n)
+	slackHi, slackLo := bits.Mul64(uint64(slack), n)
+	lo, carry := bits.Add64(lo, slackHi<<56|slackLo>>8, 0)
+	hi += carry
+
+	// Divide our value by the number of values x can hold, which yields the whole part of our fraction of the range.
+	shift := uint(8 * decisionWidth(span))
+	return hi<<(64-shift) | lo>>shift
+}
+
+// scaleFromRange obtains the smallest input to scaleToRange which produces the provided value in a range spanning
+// [0, span]. This is the inverse of scaleToRange.
+// Returns x and the byte of slack which produce the value.
+func scaleFromRange(value uint64, span uint64) (uint64, byte) {
+	// Divide value * 2^shift by the number of values in our range, rounding down. The remainder must be made up by our
+	// slack, so we pick the smallest slack which covers it. If no byte of slack covers it, the next x does on its own.
+	n := span + 1
+	shift := uint(8 * decisionWidth(span))
+	x, rem := bits.Div64(value>>(64-shift), value<<shift, n)
+	slack, slackRem := bits.Div64(rem>>56, rem<<8, n)
+	if slackRem != 0 {
+		slack++
+	}
+	if slack > math.MaxUint8 {
+		return x + 1, 0
+	}
+	return x, byte(slack)
+}
+
+// GetInt64InRange obtains an int64 within the inclusive range [min, max] from the current position in the buffer.
+// This advances the position as done by GetUint64InRange for the range [0, max - min].
+// Returns the read int64, or an error if the range is invalid or the end of stream has been reached.
+func (t *TypeProvider) GetInt64InRange(min int64, max int64) (int64, error) {
+	// Validate our parameters
+	if max < min {
+		return 0, &InvalidParamError{Param: "range", Reason: fmt.Sprintf("min: %d, max: %d", min, max)}
+	}
+
+	// Obtain an offset from our minimum. Our span is computed using unsigned arithmetic, so ranges wider than the
+	// signed range do not overflow.
+	offset, err := t.GetUint64InRange(0, uint64(max)-uint64(min))
+	return int64(uint64(min) + offset), err
+}
+
+// GetUint32InRange obtains an uint32 within the inclusive range [min, max] from the current position in the buffer.
+// This advances the position as done by GetUint64InRange.
+// Returns the read uint32, or an error if the range is invalid or the end of stream has been reached.
+func (t *TypeProvider) GetUint32InRange(min uint32, max uint32) (uint32, error) {
+	x, err := t.GetUint64InRange(uint64(min), uint64(max))
+	return uint32(x), err
+}
+
+// GetInt32InRange obtains an int32 within the inclusive range [min, max] from the current position in the buffer.
+// This advances the position as done by GetUint64InRange.
+// Returns the read int32, or an error if the range is invalid or the end of stream has been reached.
+func (t *TypeProvider) GetInt32InRange(min int32, max int32) (int32, error) {
+	x, err := t.GetInt64InRange(int64(min), int64(max))
+	return int32(x), err
+}
+
+// GetUint16InRange obtains an uint16 within the inclusive range [min, max] from the current position in the buffer.
+// This advances the position as done by GetUint64InRange.
+// Returns the read uint16, or an error if the range is invalid or the end of stream has been reached.
+func (t *TypeProvider) GetUint16InRange(min uint16, max uint16) (uint16, error) {
+	x, err := t.GetUint64InRange(uint64(min), uint64(max))
+	return uint16(x), err
+}
+
+// GetInt16InRange obtains an int16 within the inclusive range [min, max] from the current position in the buffer.
+// This advances the position as done by GetUint64InRange.
+// Returns the read int16, or an error if the range is invalid or the end of stream has been reached.
+func (t *TypeProvider) GetInt16InRange(min int16, max int16) (int16, error) {
+	x, err := t.GetInt64InRange(int64(min), int64(max))
+	return int16(x), err
+}
+
+// GetUint8InRange obtains an uint8 within the inclusive range [min, max] from the current position in the buffer.
+// This advances the position by 2, or 0 if the bounds are equal.
+// Returns the read uint8, or an error if the range is invalid or the end of stream has been reached.
+func (t *TypeProvider) GetUint8InRange(min uint8, max uint8) (uint8, error) {
+	x, err := t.GetUint64InRange(uint64(min), uint64(max))
+	return uint8(x), err
+}
+
+// GetInt8InRange obtains an int8 within the inclusive range [min, max] from the current position in the buffer.
+// This advances the position by 2, or 0 if the bounds are equal.
+// Returns the read int8, or an error if the range is invalid or the end of stream has been reached.
+func (t *TypeProvider) GetInt8InRange(min int8, max int8) (int8, error) {
+	x, err := t.GetInt64InRange(int64(min), int64(max))
+	return int8(x), err
+}
+
+// GetUintInRange obtains an uint within the inclusive range [min, max] from the current position in the buffer.
+// This advances the position as done by GetUint64InRange.
+// Returns the read uint, or an error if the range is invalid or the end of stream has been reached.
+func (t *TypeProvider) GetUintInRange(min uint, max uint) (uint, error) {
+	x, err := t.GetUint64InRange(uint64(min), uint64(max))
+	return uint(x), err
+}
+
+// GetIntInRange obtains an int within the inclusive range [min, max] from the current position in the buffer.
+// This advances the position as done by GetUint64InRange.
+// Returns the read int, or an error if the range is invalid or the end of stream has been reached.
+func (t *TypeProvider) GetIntInRange(min int, max int) (int, error) {
+	x, err := t.GetInt64InRange(int64(min), int64(max))
+	return int(x), err
+}
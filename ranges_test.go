@@ -0,0 +1,144 @@
+package go_fuzz_utils_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+func TestIntegerRanges(t *testing.T) {
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider([]byte{
+		0x80, 0x00, 0x12, 0x34, 0xFF, 0x01, 0x00, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x80, 0x00, 0x00,
+	})
+	assert.Nil(t, err)
+
+	// A range spanning 10 values consumes a single byte, followed by a byte of slack, which are scaled into it.
+	i, err := tp.GetIntInRange(-3, 6)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, i) // -3 + (0x8000 * 10 >> 16)
+
+	// Negative bounds are offset from the minimum.
+	i8, err := tp.GetInt8InRange(-128, -100)
+	assert.Nil(t, err)
+	assert.EqualValues(t, -126, i8) // -128 + (0x1234 * 29 >> 16)
+
+	// A range needing more than one byte reads them as a big-endian value, followed by a byte of slack.
+	u16, err := tp.GetUint16InRange(1000, 1000+0x1FFF)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1000+0x1FE0, u16) // 0xFF0100 * 0x2000 >> 24
+
+	// Equal bounds don't consume any data, which the following read verifies.
+	u32, err := tp.GetUint32InRange(7, 7)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 7, u32)
+
+	// The full range is read without scaling or slack.
+	i64, err := tp.GetInt64InRange(math.MinInt64, math.MaxInt64)
+	assert.Nil(t, err)
+	assert.EqualValues(t, int64(math.MinInt64)+0x0080000000000000, i64)
+
+	// Reads past the end of our data return an error.
+	_, err = tp.GetUintInRange(0, 0xFFFFFF)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrEndOfStream))
+
+	// Under ExhaustionPolicyZero, the remaining data is still read, but reads past the end of it produce the minimum.
+	assert.Nil(t, tp.SetParamsExhaustionPolicy(go_fuzz_utils.ExhaustionPolicyZero))
+	i16, err := tp.GetInt16InRange(-5, 300)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 148, i16) // -5 + (0x800000 * 306 >> 24)
+	i16, err = tp.GetInt16InRange(-5, 300)
+	assert.Nil(t, err)
+	assert.EqualValues(t, -5, i16)
+
+	// Invalid ranges return an error.
+	_, err = tp.GetIntInRange(1, 0)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+	_, err = tp.GetUint64InRange(1, 0)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+}
+
+func TestIntegerRangesDistribution(t *testing.T) {
+	// Create a type provider holding every value of two bytes, which back every read of a range within a byte.
+	data := make([]byte, 0, 0x20000)
+	for i := 0; i <= 0xFFFF; i++ {
+		data = append(data, byte(i>>8), byte(i))
+	}
+	tp, err := go_fuzz_utils.NewTypeProvider(data)
+	assert.Nil(t, err)
+
+	// Each value of a range should be produced by as many inputs as any other, give or take one.
+	for _, span := range []uint64{1, 2, 9, 128, 200, 255} {
+		assert.Nil(t, tp.Reset())
+		counts := make([]int, span+1)
+		for i := 0; i <= 0xFFFF; i++ {
+			x, err := tp.GetUint64InRange(0, span)
+			assert.Nil(t, err)
+			counts[x]++
+		}
+		minCount, maxCount := counts[0], counts[0]
+		for _, count := range counts {
+			if count < minCount {
+				minCount = count
+			} else if count > maxCount {
+				maxCount = count
+			}
+		}
+		assert.LessOrEqual(t, maxCount-minCount, 1, "span: %d", span)
+	}
+
+	// Ranges needing every byte of a uint64 read a ninth byte of slack, and can produce both of their bounds.
+	tp, err = go_fuzz_utils.NewTypeProvider([]byte{
+		0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	})
+	assert.Nil(t, err)
+	u64, err := tp.GetUint64InRange(0, 1<<63)
+	assert.Nil(t, err)
+	assert.EqualValues(t, uint64(1<<63), u64)
+	u64, err = tp.GetUint64InRange(0, 1<<63)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, u64)
+}
+
+func TestEncodeIntegerRanges(t *testing.T) {
+	// Every value within a range should be encoded as data which reproduces it.
+	type rangedStruct struct {
+		Small uint8  `fuzz:"min=3,max=131"`
+		Wide  int64  `fuzz:"min=-4611686018427387904,max=4611686018427387904"`
+		Huge  uint64 `fuzz:"min=1,max=18446744073709551615"`
+	}
+	tp, err := go_fuzz_utils.NewTypeProvider(nil)
+	assert.Nil(t, err)
+	wide := []int64{-4611686018427387904, -1, 0, 12345, 4611686018427387903, 4611686018427387904}
+	huge := []uint64{1, 2, 1 << 63, math.MaxUint64 - 1, math.MaxUint64}
+	for i := 3; i <= 131; i++ {
+		value := rangedStruct{Small: uint8(i), Wide: wide[i%len(wide)], Huge: huge[i%len(huge)]}
+		data, err := tp.Encode(&value)
+		assert.Nil(t, err)
+		filledTp, err := go_fuzz_utils.NewTypeProvider(data)
+		assert.Nil(t, err)
+		filled, err := go_fuzz_utils.Get[rangedStruct](filledTp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, value, filled)
+	}
+}
+
+func TestIntegerRangesWithinBounds(t *testing.T) {
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x1000))
+	assert.Nil(t, err)
+
+	// Obtain a number of values and verify they're always within their range.
+	for i := 0; i < 100; i++ {
+		x, err := tp.GetInt32InRange(-1000, 1000)
+		assert.Nil(t, err)
+		assert.True(t, x >= -1000 && x <= 1000)
+
+		u, err := tp.GetUint8InRange(10, 20)
+		assert.Nil(t, err)
+		assert.True(t, u >= 10 && u <= 20)
+	}
+}
@@ -0,0 +1,177 @@
+package go_fuzz_utils
+
+import (
+	"fmt"
+	"reflect"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+// parsePattern parses a regular expression in the syntax accepted by the regexp package into a simplified syntax tree
+// to generate strings from.
+// Returns the parsed syntax tree, or an error if the pattern is invalid.
+func parsePattern(pattern string) (*syntax.Regexp, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, &InvalidParamError{Param: "pattern", Reason: err.Error()}
+	}
+	return re.Simplify(), nil
+}
+
+// GetStringMatching obtains a string matching the provided regular expression, in the syntax accepted by the regexp
+// package. The syntax tree of the pattern is walked, with alternations and repetition counts decided as done for
+// slice sizes, and characters read from the current position in the buffer. Unbounded repetitions, such as "*" and
+// "+", repeat at most as many times as the maximum string length parameter. Strings only match the pattern "mostly":
+// anchors and word boundaries are ignored, so patterns relying on them may produce strings which don't match.
+// This advances the position by the amount of data the string was produced from.
+// Returns the generated string, or an error if the pattern is invalid or the end of stream has been reached.
+func (t *TypeProvider) GetStringMatching(pattern string) (string, error) {
+	// Parse our pattern, reusing any syntax tree we parsed before.
+	re, ok := t.patterns[pattern]
+	if !ok {
+		var err error
+		re, err = parsePattern(pattern)
+		if err != nil {
+			return "", err
+		}
+		if t.patterns == nil {
+			t.patterns = make(map[string]*syntax.Regexp)
+		}
+		t.patterns[pattern] = re
+	}
+	return t.getStringMatching(re)
+}
+
+// StringMatchingFiller creates a FillFunc which populates string values with strings matching the provided regular
+// expression, as done by GetStringMatching. It can be registered for a string type through RegisterFiller.
+// Returns the created FillFunc, or an error if the pattern is invalid.
+func StringMatchingFiller(pattern string) (FillFunc, error) {
+	// Parse our pattern once, so each fill only walks it.
+	re, err := parsePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return func(tp *TypeProvider, v reflect.Value) error {
+		if v.Kind() != reflect.String {
+			return fmt.Errorf("could not fill value of type %v: strings matching a pattern require a string kind", v.Type())
+		}
+		s, err := tp.getStringMatching(re)
+		if err != nil {
+			return err
+		}
+		v.SetString(s)
+		return nil
+	}, nil
+}
+
+// getStringMatching obtains a string matching the provided syntax tree. See GetStringMatching for more details.
+// Returns the generated string, or an error if the pattern cannot match any string or the end of stream has been
+// reached.
+func (t *TypeProvider) getStringMatching(re *syntax.Regexp) (string, error) {
+	var sb strings.Builder
+	err := t.writeMatching(&sb, re)
+	if err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// writeMatching writes a string matching the provided syntax tree to the provided builder.
+// Returns an error if the pattern cannot match any string or the end of stream has been reached.
+func (t *TypeProvider) writeMatching(sb *strings.Builder, re *syntax.Regexp) error {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return fmt.Errorf("could not generate a string matching %q: it matches no strings", re)
+	case syntax.OpLiteral:
+		// Literals are written as-is.
+		for _, r := range re.Rune {
+			sb.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		// A character class is a list of inclusive rune ranges to choose from, which may be empty.
+		if len(re.Rune) == 0 {
+			return fmt.Errorf("could not generate a string matching %q: it matches no strings", re)
+		}
+		r, err := t.pickRune(re.Rune)
+		if err != nil {
+			return err
+		}
+		sb.WriteRune(r)
+	case syntax.OpAnyCharNotNL:
+		r, err := t.pickRune([]rune{0, '\n' - 1, '\n' + 1, unicode.MaxRune})
+		if err != nil {
+			return err
+		}
+		sb.WriteRune(r)
+	case syntax.OpAnyChar:
+		r, err := t.pickRune([]rune{0, unicode.MaxRune})
+		if err != nil {
+			return err
+		}
+		sb.WriteRune(r)
+	case syntax.OpCapture:
+		return t.writeMatching(sb, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if err := t.writeMatching(sb, sub); err != nil {
+				return err
+			}
+		}
+	case syntax.OpAlternate:
+		// Decide which alternative to write.
+		return t.writeMatching(sb, re.Sub[t.getRandomSize(0, len(re.Sub)-1)])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		// Decide how many times to repeat our expression, limiting unbounded repetitions to our maximum string length.
+		minCount, maxCount := re.Min, re.Max
+		if re.Op == syntax.OpStar {
+			minCount, maxCount = 0, -1
+		} else if re.Op == syntax.OpPlus {
+			minCount, maxCount = 1, -1
+		} else if re.Op == syntax.OpQuest {
+			minCount, maxCount = 0, 1
+		}
+		if maxCount < 0 {
+			maxCount = t.stringMaxLength
+			if maxCount < minCount {
+				maxCount = minCount
+			}
+		}
+		count := t.getRandomSize(minCount, maxCount)
+		for i := 0; i < count; i++ {
+			if err := t.writeMatching(sb, re.Sub[0]); err != nil {
+				return err
+			}
+		}
+	default:
+		// Empty matches, anchors and word boundaries do not produce any characters.
+	}
+	return nil
+}
+
+// pickRune obtains a rune from the provided list of inclusive rune ranges, as found in character classes, reading
+// only as many bytes as the number of runes in the ranges needs.
+// Returns the chosen rune, or an error if the end of stream has been reached.
+func (t *TypeProvider) pickRune(ranges []rune) (rune, error) {
+	// Determine the number of runes in our ranges.
+	total := uint64(0)
+	for i := 0; i < len(ranges); i += 2 {
+		total += uint64(ranges[i+1]-ranges[i]) + 1
+	}
+
+	// Pick an index, and find the range it falls into.
+	x, err := t.GetUint64InRange(0, total-1)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < len(ranges); i += 2 {
+		size := uint64(ranges[i+1]-ranges[i]) + 1
+		if x < size {
+			return ranges[i] + rune(x), nil
+		}
+		x -= size
+	}
+
+	// This is unreachable, as x is always less than the total number of runes.
+	panic("rune index exceeded character class size")
+}
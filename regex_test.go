@@ -0,0 +1,106 @@
+package go_fuzz_utils_test
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+func TestGetStringMatching(t *testing.T) {
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x10000))
+	assert.Nil(t, err)
+
+	// Generate a number of strings for each pattern and verify they match it.
+	patterns := []string{
+		`^[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,4}$`,
+		`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(-[0-9A-Za-z-]+)?$`,
+		`^\d{4}-\d{2}-\d{2}$`,
+		`^(GET|POST|PUT) /[^ ]*$`,
+		`^[[:alpha:]_][[:word:]]{0,7}$`,
+		`^(?i:abc)x?$`,
+	}
+	for _, pattern := range patterns {
+		re := regexp.MustCompile(pattern)
+		for i := 0; i < 20; i++ {
+			s, err := tp.GetStringMatching(pattern)
+			assert.Nil(t, err)
+			assert.True(t, re.MatchString(s), "%q does not match %q", s, pattern)
+		}
+	}
+
+	// Unbounded repetitions are limited by our maximum string length.
+	assert.Nil(t, tp.SetParamsStringBounds(0, 3))
+	for i := 0; i < 20; i++ {
+		s, err := tp.GetStringMatching(`a+b*`)
+		assert.Nil(t, err)
+		assert.LessOrEqual(t, len(s), 6)
+	}
+
+	// Invalid patterns, and patterns matching nothing, return an error.
+	_, err = tp.GetStringMatching(`[a-`)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+	_, err = tp.GetStringMatching(`[^\x00-\x{10FFFF}]`)
+	assert.NotNil(t, err)
+
+	// Reads past the end of our data return an error.
+	tp, err = go_fuzz_utils.NewTypeProvider([]byte{0x01})
+	assert.Nil(t, err)
+	s, err := tp.GetStringMatching(`[ab][cd]`)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrEndOfStream))
+	assert.EqualValues(t, "", s)
+}
+
+type email string
+
+type patternStruct struct {
+	ID      string  `fuzz:"regex=^[A-Z]{2,3}-\\d{1,4}$"`
+	Version *string `fuzz:"nilbias=0, regex=^v\\d,\\d$"`
+	Email   email
+}
+
+func TestFillStringMatching(t *testing.T) {
+	// Create our type provider, registering a filler for our email type.
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x10000))
+	assert.Nil(t, err)
+	filler, err := go_fuzz_utils.StringMatchingFiller(`^[a-z]{1,8}@example\.(com|org)$`)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.RegisterFiller(reflect.TypeOf(email("")), filler))
+
+	// Fill a number of values and verify they match their patterns.
+	for i := 0; i < 20; i++ {
+		var v patternStruct
+		assert.Nil(t, tp.Fill(&v))
+		assert.Regexp(t, `^[A-Z]{2,3}-\d{1,4}$`, v.ID)
+		if assert.NotNil(t, v.Version) {
+			assert.Regexp(t, `^v\d,\d$`, *v.Version)
+		}
+		assert.Regexp(t, `^[a-z]{1,8}@example\.(com|org)$`, string(v.Email))
+	}
+
+	// Invalid patterns, and patterns for other kinds or combined with bounds, are rejected.
+	_, err = go_fuzz_utils.StringMatchingFiller(`(`)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrInvalidParam))
+	invalidTags := []interface{}{
+		&struct {
+			S string `fuzz:"regex=("`
+		}{},
+		&struct {
+			I int `fuzz:"regex=\\d+"`
+		}{},
+		&struct {
+			S string `fuzz:"max=3,regex=a+"`
+		}{},
+	}
+	for _, v := range invalidTags {
+		assert.True(t, errors.Is(tp.Fill(v), go_fuzz_utils.ErrInvalidParam))
+	}
+
+	// Values generated from a pattern cannot be encoded.
+	_, err = tp.Encode(&patternStruct{})
+	assert.NotNil(t, err)
+}
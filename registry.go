@@ -0,0 +1,99 @@
+package go_fuzz_utils
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FillFunc describes a custom function used by Fill to populate a value of a given type. The provided value is
+// always settable.
+// Returns an error if one is encountered.
+type FillFunc func(tp *TypeProvider, v reflect.Value) error
+
+// FuzzFillable describes a type which populates itself using a TypeProvider. When Fill encounters a value whose
+// pointer implements this interface, FuzzFill is called rather than populating the value based on its kind.
+// Implementations must not call Fill on the receiver itself, as this would recurse indefinitely.
+type FuzzFillable interface {
+	// FuzzFill populates the receiver using the provided TypeProvider.
+	// Returns an error if one is encountered.
+	FuzzFill(tp *TypeProvider) error
+}
+
+// RegisterImplementations registers concrete types which Fill may use to populate values of the provided interface
+// type. When filling an interface value, one of its registered types is chosen using the fuzz data and filled
+// recursively. Interface values are set as nil with the same probability as pointers. Interface types without any
+// registered implementations are skipped by Fill.
+// Returns an error if the provided type is not an interface type, or if any provided type does not implement it.
+func (t *TypeProvider) RegisterImplementations(ifaceType reflect.Type, implTypes ...reflect.Type) error {
+	// Validate our interface type
+	if ifaceType == nil || ifaceType.Kind() != reflect.Interface {
+		return &InvalidParamError{Param: "interface type", Reason: fmt.Sprintf("%v is not an interface type", ifaceType)}
+	}
+
+	// Validate each of our implementation types
+	for _, implType := range implTypes {
+		if implType == nil || !implType.Implements(ifaceType) {
+			return &InvalidParamError{Param: "implementation type", Reason: fmt.Sprintf("%v does not implement %v", implType, ifaceType)}
+		}
+	}
+
+	// Add our implementations to the registry.
+	if t.implementations == nil {
+		t.implementations = make(map[reflect.Type][]reflect.Type)
+	}
+	t.implementations[ifaceType] = append(t.implementations[ifaceType], implTypes...)
+	return nil
+}
+
+// GetImplementations obtains the concrete types registered to populate values of the provided interface type.
+func (t *TypeProvider) GetImplementations(ifaceType reflect.Type) []reflect.Type {
+	return t.implementations[ifaceType]
+}
+
+// RegisterFiller registers a custom function which Fill uses to populate values of the provided type, rather than
+// populating them based on their kind. Registered functions take precedence over FuzzFillable implementations and
+// are used at every depth, including within slices, maps and pointers. Registering a function for a type which already
+// has one replaces it.
+// Returns an error if the provided type or function is nil.
+func (t *TypeProvider) RegisterFiller(typ reflect.Type, fn FillFunc) error {
+	// Validate our parameters
+	if typ == nil || fn == nil {
+		return &InvalidParamError{Param: "filler", Reason: fmt.Sprintf("type (%v) and function must not be nil", typ)}
+	}
+
+	// Add our function to the registry.
+	if t.fillers == nil {
+		t.fillers = make(map[reflect.Type]FillFunc)
+	}
+	t.fillers[typ] = fn
+	return nil
+}
+
+// hasCustomFill determines whether a value is populated by a registered FillFunc for its type, or its FuzzFillable
+// implementation, rather than based on its kind.
+func (t *TypeProvider) hasCustomFill(v reflect.Value) bool {
+	if _, ok := t.fillers[v.Type()]; ok {
+		return true
+	} else if !v.CanAddr() {
+		return false
+	}
+	_, fillable := v.Addr().Interface().(FuzzFillable)
+	return fillable
+}
+
+// fillCustom populates a value using a registered FillFunc for its type, or its FuzzFillable implementation.
+// Returns a boolean indicating whether a custom fill method existed for the value, and an error if one is encountered.
+func (t *TypeProvider) fillCustom(v reflect.Value) (bool, error) {
+	// If we have a registered function for this type, use it.
+	if fn, ok := t.fillers[v.Type()]; ok {
+		return true, fn(t, v)
+	}
+
+	// If the type populates itself through its pointer, use that.
+	if v.CanAddr() {
+		if fillable, ok := v.Addr().Interface().(FuzzFillable); ok {
+			return true, fillable.FuzzFill(t)
+		}
+	}
+	return false, nil
+}
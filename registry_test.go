@@ -0,0 +1,181 @@
+package go_fuzz_utils_test
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+type shape interface {
+	Area() float64
+}
+
+type circle struct {
+	Radius float64
+}
+
+func (c circle) Area() float64 { return 3.14 * c.Radius * c.Radius }
+
+type square struct {
+	Side uint16
+	Name string
+}
+
+func (s *square) Area() float64 { return float64(s.Side) * float64(s.Side) }
+
+type shapeStruct struct {
+	Shape    shape
+	Shapes   []shape
+	Stringer fmt.Stringer
+}
+
+func TestFillInterfaces(t *testing.T) {
+	// Create our fuzz data, using random data so that different implementations are chosen.
+	b := make([]byte, 0x1000)
+	rand.New(rand.NewSource(0)).Read(b)
+
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider(b)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsBiasesCommon(0, 0))
+	assert.Nil(t, tp.SetParamsSliceBounds(10, 10))
+
+	// Register our implementations for our shape interface, but not for fmt.Stringer.
+	shapeType := reflect.TypeOf((*shape)(nil)).Elem()
+	err = tp.RegisterImplementations(shapeType, reflect.TypeOf(circle{}), reflect.TypeOf(&square{}))
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(tp.GetImplementations(shapeType)))
+
+	// Fill our structure.
+	st := shapeStruct{}
+	err = tp.Fill(&st)
+	assert.Nil(t, err)
+
+	// Verify our registered interface values were populated with concrete types, and unregistered ones were skipped.
+	assert.NotNil(t, st.Shape)
+	assert.EqualValues(t, 10, len(st.Shapes))
+	implsSeen := make(map[reflect.Type]bool)
+	for _, s := range append(st.Shapes, st.Shape) {
+		if assert.NotNil(t, s) {
+			implsSeen[reflect.TypeOf(s)] = true
+		}
+	}
+	assert.EqualValues(t, 2, len(implsSeen))
+	assert.Nil(t, st.Stringer)
+
+	// Fill our structure again with a full nil bias, which should apply to interfaces.
+	assert.Nil(t, tp.SetParamsBiasesCommon(1, 0))
+	err = tp.Fill(&st)
+	assert.Nil(t, err)
+	assert.Nil(t, st.Shape)
+}
+
+func TestRegisterImplementationsErrors(t *testing.T) {
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x10))
+	assert.Nil(t, err)
+
+	// Registering implementations for a non-interface type should fail.
+	err = tp.RegisterImplementations(reflect.TypeOf(circle{}), reflect.TypeOf(circle{}))
+	assert.NotNil(t, err)
+
+	// Registering a type which does not implement the interface should fail (square implements it by pointer).
+	shapeType := reflect.TypeOf((*shape)(nil)).Elem()
+	err = tp.RegisterImplementations(shapeType, reflect.TypeOf(square{}))
+	assert.NotNil(t, err)
+	assert.EqualValues(t, 0, len(tp.GetImplementations(shapeType)))
+
+	// Registering implementations from values should succeed.
+	err = go_fuzz_utils.RegisterImplementationsOf[shape](tp, circle{}, &square{})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []reflect.Type{reflect.TypeOf(circle{}), reflect.TypeOf(&square{})}, tp.GetImplementations(shapeType))
+
+	// Registering a nil value should fail.
+	err = go_fuzz_utils.RegisterImplementationsOf[shape](tp, nil)
+	assert.NotNil(t, err)
+}
+
+type validatedID string
+
+func (id *validatedID) FuzzFill(tp *go_fuzz_utils.TypeProvider) error {
+	// Obtain a number and use it to construct a valid identifier.
+	x, err := tp.GetUint16()
+	if err != nil {
+		return err
+	}
+	*id = validatedID(fmt.Sprintf("id-%d", x))
+	return nil
+}
+
+type customStruct struct {
+	ID      validatedID
+	IDs     []validatedID
+	IDPtr   *validatedID
+	IDMap   map[validatedID]validatedID
+	Number  *big.Int
+	Numbers []*big.Int
+	Evens   []int
+}
+
+func TestFillCustomFillers(t *testing.T) {
+	// Create our fuzz data
+	b := generateTestData(0x1000)
+
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider(b)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsBiasesCommon(0, 0))
+	assert.Nil(t, tp.SetParamsSliceBounds(1, 5))
+	assert.Nil(t, tp.SetParamsMapBounds(1, 5))
+
+	// Register a filler for big integers which creates them from an int64.
+	err = tp.RegisterFiller(reflect.TypeOf(&big.Int{}), func(tp *go_fuzz_utils.TypeProvider, v reflect.Value) error {
+		x, err := tp.GetInt64()
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(big.NewInt(x)))
+		return nil
+	})
+	assert.Nil(t, err)
+
+	// Register a filler for ints which only produces even numbers.
+	err = go_fuzz_utils.RegisterFillerOf[int](tp, func(tp *go_fuzz_utils.TypeProvider) (int, error) {
+		x, err := tp.GetInt()
+		return x &^ 1, err
+	})
+	assert.Nil(t, err)
+
+	// Fill our structure.
+	st := customStruct{}
+	err = tp.Fill(&st)
+	assert.Nil(t, err)
+
+	// Verify our custom fill methods were used at every depth.
+	ids := append([]validatedID{st.ID, *st.IDPtr}, st.IDs...)
+	for k, v := range st.IDMap {
+		ids = append(ids, k, v)
+	}
+	for _, id := range ids {
+		assert.True(t, strings.HasPrefix(string(id), "id-"))
+	}
+	assert.NotNil(t, st.Number)
+	assert.NotEmpty(t, st.Numbers)
+	for _, n := range st.Numbers {
+		assert.NotNil(t, n)
+	}
+	assert.NotEmpty(t, st.Evens)
+	for _, x := range st.Evens {
+		assert.EqualValues(t, 0, x%2)
+	}
+
+	// Registering a nil filler should fail.
+	assert.NotNil(t, tp.RegisterFiller(reflect.TypeOf(0), nil))
+	assert.NotNil(t, go_fuzz_utils.RegisterFillerOf[int](tp, nil))
+}
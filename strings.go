@@ -0,0 +1,210 @@
+package go_fuzz_utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// StringMode describes how strings are produced from the input data.
+type StringMode int
+
+const (
+	// StringModeRaw indicates strings are produced by casting raw bytes, so they may not be valid UTF-8. String lengths
+	// always count bytes in this mode. This is the default mode.
+	StringModeRaw StringMode = iota
+	// StringModeUTF8 indicates strings consist of valid UTF-8. Each character is decoded from the data, so valid UTF-8
+	// in the data is read unchanged, and any byte which does not start a valid encoding produces an ASCII character.
+	StringModeUTF8
+	// StringModeASCII indicates strings consist of ASCII characters, each produced from a single byte.
+	StringModeASCII
+	// StringModePrintable indicates strings consist of printable characters, as defined by unicode.IsPrint. Characters
+	// are produced as with StringModeUTF8, with non-printable characters mapped to printable ASCII characters.
+	StringModePrintable
+	// StringModeRunes indicates strings consist of characters from a custom set provided through
+	// SetParamsStringRunes, each chosen as done by PickIndex.
+	StringModeRunes
+)
+
+// String obtains a human-readable name for the StringMode.
+func (m StringMode) String() string {
+	switch m {
+	case StringModeRaw:
+		return "raw"
+	case StringModeUTF8:
+		return "utf8"
+	case StringModeASCII:
+		return "ascii"
+	case StringModePrintable:
+		return "printable"
+	case StringModeRunes:
+		return "runes"
+	default:
+		return fmt.Sprintf("StringMode(%d)", int(m))
+	}
+}
+
+// GetParamsStringMode obtains the mode used to produce strings.
+func (t *TypeProvider) GetParamsStringMode() StringMode {
+	return t.stringMode
+}
+
+// SetParamsStringMode sets the mode used to produce strings, which applies to GetString, GetFixedString and Fill.
+// Returns an error if the string mode is invalid, or is StringModeRunes without a rune set having been provided.
+func (t *TypeProvider) SetParamsStringMode(mode StringMode) error {
+	// Validate our parameters and set them accordingly
+	if mode < StringModeRaw || mode > StringModeRunes {
+		return &InvalidParamError{Param: "string mode", Reason: mode.String()}
+	}
+	if mode == StringModeRunes && len(t.stringRunes) == 0 {
+		return &InvalidParamError{Param: "string mode", Reason: "a rune set must be provided through SetParamsStringRunes"}
+	}
+	t.stringMode = mode
+	return nil
+}
+
+// GetParamsStringRunes obtains the rune set used to produce strings with StringModeRunes.
+func (t *TypeProvider) GetParamsStringRunes() []rune {
+	return t.stringRunes
+}
+
+// SetParamsStringRunes sets the rune set used to produce strings, and sets the string mode to StringModeRunes.
+// Returns an error if the rune set is empty or contains invalid runes.
+func (t *TypeProvider) SetParamsStringRunes(runes []rune) error {
+	// Validate our parameters and set them accordingly
+	if len(runes) == 0 {
+		return &InvalidParamError{Param: "string runes", Reason: "at least one rune must be provided"}
+	}
+	for _, r := range runes {
+		if !utf8.ValidRune(r) {
+			return &InvalidParamError{Param: "string runes", Reason: fmt.Sprintf("rune %U is not valid", r)}
+		}
+	}
+	t.stringRunes = append([]rune(nil), runes...)
+	t.stringMode = StringModeRunes
+	return nil
+}
+
+// GetParamsStringCountRunes obtains a parameter indicating whether string lengths count runes rather than bytes.
+func (t *TypeProvider) GetParamsStringCountRunes() bool {
+	return t.stringCountRunes
+}
+
+// SetParamsStringCountRunes sets a parameter indicating whether string lengths, such as those set through
+// SetParamsStringBounds and provided to GetFixedString, count runes rather than bytes. This does not apply to
+// StringModeRaw, where lengths always count bytes.
+func (t *TypeProvider) SetParamsStringCountRunes(countRunes bool) {
+	t.stringCountRunes = countRunes
+}
+
+// readString obtains a string of the provided length from the current position in the buffer, according to the string
+// mode. The length counts runes or bytes depending on the string count runes parameter. When counting bytes with
+// StringModeRunes, the string ends early if no rune in the set fits the remaining length. Under ExhaustionPolicyZero,
+// the string also ends early once the data is exhausted, as it does for StringModeRaw.
+// Returns the read string, or an error if the end of stream has been reached.
+func (t *TypeProvider) readString(length int) (string, error) {
+	// Raw strings are cast directly from our bytes.
+	if t.stringMode == StringModeRaw {
+		b, err := t.GetNBytes(length)
+		return string(b), err
+	}
+
+	// Read each of our runes, limiting their size to the length remaining if we're counting bytes.
+	var sb strings.Builder
+	for i := 0; i < length; {
+		maxSize := utf8.UTFMax
+		if !t.stringCountRunes {
+			maxSize = length - sb.Len()
+		}
+		r, ok, err := t.readRune(maxSize)
+		if err != nil {
+			return "", err
+		} else if !ok {
+			break
+		}
+		sb.WriteRune(r)
+
+		// Advance by a rune or by the bytes we've written, depending on our unit.
+		if t.stringCountRunes {
+			i++
+		} else {
+			i = sb.Len()
+		}
+	}
+	return sb.String(), nil
+}
+
+// readRune obtains a rune from the current position in the buffer according to the string mode, whose UTF-8 encoding
+// is no larger than the provided size. Under ExhaustionPolicyZero, no rune is produced once the data is exhausted.
+// Returns the read rune, a boolean indicating whether a rune which fits the size could be produced, or an error if the
+// end of stream has been reached.
+func (t *TypeProvider) readRune(maxSize int) (rune, bool, error) {
+	// A custom rune set picks from the runes which fit our size.
+	if t.stringMode == StringModeRunes {
+		runes := fittingRunes(t.stringRunes, maxSize)
+		if len(runes) == 0 || t.exhaust(rangeWidth(uint64(len(runes)-1))) {
+			return 0, false, nil
+		}
+		i, err := t.PickIndex(len(runes))
+		if err != nil {
+			return 0, false, err
+		}
+		return runes[i], true, nil
+	}
+
+	// ASCII characters are obtained from the lower bits of a byte.
+	if t.stringMode == StringModeASCII {
+		if t.exhaust(1) {
+			return 0, false, nil
+		}
+		b, err := t.getFixedBytes(1)
+		if err != nil {
+			return 0, false, err
+		}
+		return rune(b[0] & 0x7F), true, nil
+	}
+
+	// Other modes decode a rune from our data, unless we're exhausted.
+	if t.exhaust(1) {
+		return 0, false, nil
+	}
+	err := t.validateBounds(1)
+	if err != nil {
+		return 0, false, err
+	}
+
+	// Decode our rune from our remaining data, limited to our size. If our data doesn't start with a valid encoding,
+	// we consume a single byte and produce an ASCII character from it.
+	available := t.data[t.position:t.end]
+	if len(available) > maxSize {
+		available = available[:maxSize]
+	}
+	r, size := utf8.DecodeRune(available)
+	if r == utf8.RuneError && size <= 1 {
+		r, size = rune(available[0]&0x7F), 1
+	}
+	t.position += size
+
+	// Map non-printable characters to printable ASCII characters if needed.
+	if t.stringMode == StringModePrintable && !unicode.IsPrint(r) {
+		r = ' ' + r%('~'-' '+1)
+	}
+	return r, true, nil
+}
+
+// fittingRunes obtains the runes from the provided set whose UTF-8 encoding is no larger than the provided size.
+// Returns the fitting runes, which is the provided set itself if all of them fit.
+func fittingRunes(runes []rune, maxSize int) []rune {
+	// If every rune fits, avoid creating a new set.
+	if maxSize >= utf8.UTFMax {
+		return runes
+	}
+	var fitting []rune
+	for _, r := range runes {
+		if utf8.RuneLen(r) <= maxSize {
+			fitting = append(fitting, r)
+		}
+	}
+	return fitting
+}
@@ -0,0 +1,197 @@
+package go_fuzz_utils_test
+
+import (
+	"errors"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+func TestStringModes(t *testing.T) {
+	// Create our data with valid UTF-8, followed by invalid UTF-8 and non-printable characters.
+	b := append([]byte("aé世"), 0xFF, 0xC3, 0x07)
+
+	// In raw mode, strings are cast from our bytes.
+	tp, err := go_fuzz_utils.NewTypeProvider(b)
+	assert.Nil(t, err)
+	assert.EqualValues(t, go_fuzz_utils.StringModeRaw, tp.GetParamsStringMode())
+	s, err := tp.GetFixedString(len(b))
+	assert.Nil(t, err)
+	assert.EqualValues(t, string(b), s)
+
+	// In UTF-8 mode, valid UTF-8 is read unchanged and invalid bytes produce ASCII characters.
+	assert.Nil(t, tp.SetParamsStringMode(go_fuzz_utils.StringModeUTF8))
+	assert.Nil(t, tp.Reset())
+	s, err = tp.GetFixedString(len(b))
+	assert.Nil(t, err)
+	assert.EqualValues(t, "aé世\x7FC\x07", s)
+
+	// In ASCII mode, each byte produces a character.
+	assert.Nil(t, tp.SetParamsStringMode(go_fuzz_utils.StringModeASCII))
+	assert.Nil(t, tp.Reset())
+	s, err = tp.GetFixedString(3)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "aC)", s)
+
+	// In printable mode, non-printable characters are mapped to printable ones.
+	assert.Nil(t, tp.SetParamsStringMode(go_fuzz_utils.StringModePrintable))
+	assert.Nil(t, tp.Reset())
+	s, err = tp.GetFixedString(len(b))
+	assert.Nil(t, err)
+	assert.EqualValues(t, "aé世@C'", s)
+
+	// Reads past the end of our data return an error.
+	_, err = tp.GetFixedString(1)
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrEndOfStream))
+
+	// Invalid modes return an error, including a rune mode without runes.
+	assert.True(t, errors.Is(tp.SetParamsStringMode(go_fuzz_utils.StringMode(-1)), go_fuzz_utils.ErrInvalidParam))
+	assert.True(t, errors.Is(tp.SetParamsStringMode(go_fuzz_utils.StringModeRunes), go_fuzz_utils.ErrInvalidParam))
+	assert.True(t, errors.Is(tp.SetParamsStringRunes(nil), go_fuzz_utils.ErrInvalidParam))
+	assert.True(t, errors.Is(tp.SetParamsStringRunes([]rune{0xD800}), go_fuzz_utils.ErrInvalidParam))
+}
+
+func TestStringLengthUnits(t *testing.T) {
+	// Create our type provider with multi-byte characters.
+	b := []byte("世界éa")
+	tp, err := go_fuzz_utils.NewTypeProvider(b)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsStringMode(go_fuzz_utils.StringModeUTF8))
+
+	// Counting runes, we read each of our characters.
+	tp.SetParamsStringCountRunes(true)
+	assert.True(t, tp.GetParamsStringCountRunes())
+	s, err := tp.GetFixedString(4)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "世界éa", s)
+
+	// Counting bytes, characters which don't fit the remaining length produce ASCII characters instead.
+	tp.SetParamsStringCountRunes(false)
+	assert.Nil(t, tp.Reset())
+	s, err = tp.GetFixedString(4)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 4, len(s))
+	assert.EqualValues(t, "世g", s)
+
+	// Custom rune sets choose from the runes fitting the remaining length, consuming no data if a single rune fits, and
+	// ending early if none fit.
+	assert.Nil(t, tp.SetParamsStringRunes([]rune{'x', '世'}))
+	assert.EqualValues(t, go_fuzz_utils.StringModeRunes, tp.GetParamsStringMode())
+	assert.EqualValues(t, []rune{'x', '世'}, tp.GetParamsStringRunes())
+	tp, err = go_fuzz_utils.NewTypeProvider([]byte{0x80, 0x00})
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsStringRunes([]rune{'x', '世'}))
+	s, err = tp.GetFixedString(5)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "世xx", s)
+	assert.Nil(t, tp.SetParamsStringRunes([]rune{'世'}))
+	s, err = tp.GetFixedString(2)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "", s)
+}
+
+type stringStruct struct {
+	S    string
+	Strs []string
+	Tag  string `fuzz:"min=2,max=4"`
+}
+
+func TestFillStringModes(t *testing.T) {
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider(generateTestData(0x10000))
+	assert.Nil(t, err)
+	tp.SetParamsStringCountRunes(true)
+
+	// Fill a number of values and verify our strings are printable, with lengths counting runes.
+	assert.Nil(t, tp.SetParamsStringMode(go_fuzz_utils.StringModePrintable))
+	for i := 0; i < 20; i++ {
+		var v stringStruct
+		assert.Nil(t, tp.Fill(&v))
+		for _, s := range append(v.Strs, v.S, v.Tag) {
+			assert.True(t, utf8.ValidString(s))
+			for _, r := range s {
+				assert.True(t, unicode.IsPrint(r))
+			}
+		}
+		assert.True(t, utf8.RuneCountInString(v.Tag) >= 2 && utf8.RuneCountInString(v.Tag) <= 4)
+	}
+}
+
+func TestEncodeStringModes(t *testing.T) {
+	modes := []go_fuzz_utils.StringMode{
+		go_fuzz_utils.StringModeRaw, go_fuzz_utils.StringModeUTF8, go_fuzz_utils.StringModeASCII,
+		go_fuzz_utils.StringModePrintable, go_fuzz_utils.StringModeRunes,
+	}
+	for _, mode := range modes {
+		for _, countRunes := range []bool{false, true} {
+			// Create a type provider in our mode.
+			newTypeProvider := func(b []byte) *go_fuzz_utils.TypeProvider {
+				tp, err := go_fuzz_utils.NewTypeProvider(b)
+				assert.Nil(t, err)
+				assert.Nil(t, tp.SetParamsStringRunes([]rune("abcé世")))
+				assert.Nil(t, tp.SetParamsStringMode(mode))
+				tp.SetParamsStringCountRunes(countRunes)
+				return tp
+			}
+
+			// Encode a value and fill a new value from the encoded data.
+			value := stringStruct{S: "abc", Strs: []string{"", "cab"}, Tag: "ab"}
+			if mode != go_fuzz_utils.StringModeASCII {
+				value.Strs = append(value.Strs, "é世a")
+			}
+			data, err := newTypeProvider(nil).Encode(&value)
+			assert.Nil(t, err)
+			var filled stringStruct
+			assert.Nil(t, newTypeProvider(data).Fill(&filled))
+			assert.EqualValues(t, value, filled)
+
+			// Strings which cannot be produced in our mode cannot be encoded.
+			invalid := "\xFF\x07"
+			_, err = newTypeProvider(nil).Encode(&invalid)
+			assert.Equal(t, mode != go_fuzz_utils.StringModeRaw, err != nil)
+		}
+	}
+}
+
+func TestStringModesExhausted(t *testing.T) {
+	modes := []go_fuzz_utils.StringMode{
+		go_fuzz_utils.StringModeRaw, go_fuzz_utils.StringModeUTF8, go_fuzz_utils.StringModeASCII,
+		go_fuzz_utils.StringModePrintable, go_fuzz_utils.StringModeRunes,
+	}
+	for _, mode := range modes {
+		t.Run(mode.String(), func(t *testing.T) {
+			// Create a type provider with the data for a single character, producing zero values past the end of it.
+			// Custom rune sets pick each rune with a byte of slack.
+			data := []byte("a")
+			if mode == go_fuzz_utils.StringModeRunes {
+				data = []byte("a\x00")
+			}
+			tp, err := go_fuzz_utils.NewTypeProvider(data)
+			assert.Nil(t, err)
+			assert.Nil(t, tp.SetParamsExhaustionPolicy(go_fuzz_utils.ExhaustionPolicyZero))
+			if mode == go_fuzz_utils.StringModeRunes {
+				assert.Nil(t, tp.SetParamsStringRunes([]rune("xyz")))
+			} else {
+				assert.Nil(t, tp.SetParamsStringMode(mode))
+			}
+
+			// Strings should end once we're exhausted, rather than being padded with zero characters.
+			s, err := tp.GetFixedString(5)
+			assert.Nil(t, err)
+			assert.True(t, tp.Exhausted())
+			assert.NotContains(t, s, "\x00")
+			assert.LessOrEqual(t, len(s), 1)
+			if mode != go_fuzz_utils.StringModeRaw {
+				assert.Len(t, s, 1)
+			}
+
+			// Further strings should be empty.
+			s, err = tp.GetFixedString(5)
+			assert.Nil(t, err)
+			assert.Empty(t, s)
+		})
+	}
+}
@@ -2,12 +2,11 @@ package go_fuzz_utils
 
 import (
 	"encoding/binary"
-	"errors"
 	"fmt"
 	"math"
 	"math/rand"
 	"reflect"
-	"unsafe"
+	"regexp/syntax"
 )
 
 // TypeProvider ingests an arbitrary byte array and uses it to extract common data types and populate structures
@@ -17,8 +16,13 @@ type TypeProvider struct {
 	data []byte
 	// position represents the offset into the data buffer which we are currently located at.
 	position int
+	// end represents the offset into the data buffer which data can be read up to. When using DecisionModeTail, this
+	// moves backwards as structural decisions are read from the end of the buffer.
+	end int
+	// decisionMode describes how nil/skip probability and array/map/string sizes are determined.
+	decisionMode DecisionMode
 	// randomProvider represents a seeded random provider used to determine nil/skip probability and array/map/string
-	// sizes.
+	// sizes when using DecisionModeSeeded.
 	randomProvider *rand.Rand // initialized after seed is obtained from first few bytes of data
 
 	// sliceMinSize describes the minimum size a slice value will be generated as
@@ -35,9 +39,34 @@ type TypeProvider struct {
 	// mapNilBias describes the probability of a map being set as nil (represented as a float between 0 and 1)
 	mapNilBias float32
 
+	// chanMinSize describes the minimum capacity a channel value will be generated with
+	chanMinSize int
+	// chanMaxSize describes the maximum capacity a channel value will be generated with
+	chanMaxSize int
+	// chanNilBias describes the probability of a channel being set as nil (represented as a float between 0 and 1)
+	chanNilBias float32
+	// chanCloseBias describes the probability of a channel being closed after its elements are sent (represented as a
+	// float between 0 and 1)
+	chanCloseBias float32
+
 	// ptrNilBias describes the probability of a pointer being set as nil (represented as a float between 0 and 1)
 	ptrNilBias float32
 
+	// funcNilBias describes the probability of a func-typed value being set as nil (represented as a float between 0
+	// and 1)
+	funcNilBias float32
+	// funcErrorBias describes the probability of an error result of a synthesized function being set to a non-nil
+	// error (represented as a float between 0 and 1)
+	funcErrorBias float32
+	// funcCalls describes the calls made to functions synthesized by Fill, in the order they were made.
+	funcCalls []FuncCall
+
+	// stringMode describes how strings are produced from the input data.
+	stringMode StringMode
+	// stringRunes describes the rune set strings are produced from when using StringModeRunes.
+	stringRunes []rune
+	// stringCountRunes indicates whether string lengths count runes rather than bytes.
+	stringCountRunes bool
 	// stringMinLength describes the minimum size a string value will be generated as
 	stringMinLength int
 	// stringMaxLength describes the maximum size a string value will be generated as
@@ -46,11 +75,63 @@ type TypeProvider struct {
 	// depthLimit describes the maximum struct depth that values will be filled at. A value of zero indicates unlimited
 	// depth.
 	depthLimit int // zero indicates infinite depth
+	// depthCountContainers indicates whether pointers, slices, arrays, maps, channels and interfaces count as a level of
+	// depth, in addition to structs.
+	depthCountContainers bool
+	// byteOrder describes the byte order multi-byte integer and float values are read with.
+	byteOrder binary.ByteOrder
+	// exhaustionPolicy describes how reads past the end of the data are handled.
+	exhaustionPolicy ExhaustionPolicy
+	// exhausted indicates whether a read went past the end of the data under ExhaustionPolicyZero.
+	exhausted bool
+
+	// varintIntegers indicates whether Fill reads multi-byte integer values as varints rather than fixed-width values.
+	varintIntegers bool
+	// floatMode describes how float and complex values are produced when using Fill.
+	floatMode FloatMode
+	// floatSpecialBias describes the probability of float values being produced as NaN or an infinity when using Fill
+	// (represented as a float between 0 and 1)
+	floatSpecialBias float32
+
+	// interestingValueBias describes the probability of numeric values being produced from a table of interesting
+	// values rather than read (represented as a float between 0 and 1)
+	interestingValueBias float32
+	// interestingValues describes the tables of interesting values for each numeric kind, represented by their bits.
+	interestingValues map[reflect.Kind][]uint64
+
 	// fillUnexportedFields indicates whether unexported fields should be filled.
 	fillUnexportedFields bool
 	// skipFieldBias describes the probability of a field being skipped during struct fill operations (represented as
 	// a float between 0 and 1)
 	skipFieldBias float32
+
+	// dictionary describes the tokens which may be emitted or spliced into strings and byte slices.
+	dictionary [][]byte
+	// dictionaryBias describes the probability of strings and byte slices being produced using the dictionary
+	// (represented as a float between 0 and 1)
+	dictionaryBias float32
+
+	// aliasBias describes the probability of pointers, slices and maps reusing a value created earlier in the same
+	// Fill call (represented as a float between 0 and 1)
+	aliasBias float32
+	// aliases describes the pointers, slices and maps created during the current Fill call which may be reused, by
+	// type. This is nil if no Fill call is in progress.
+	aliases map[reflect.Type][]reflect.Value
+
+	// mutateReplaceBias describes the probability of a value being replaced when using Mutate (represented as a float
+	// between 0 and 1)
+	mutateReplaceBias float32
+	// mutateBias describes the probability of a value which is not replaced being slightly mutated when using Mutate
+	// (represented as a float between 0 and 1)
+	mutateBias float32
+
+	// patterns describes the syntax trees of the patterns provided to GetStringMatching, so they're only parsed once.
+	patterns map[string]*syntax.Regexp
+
+	// implementations describes the concrete types registered to populate values of a given interface type.
+	implementations map[reflect.Type][]reflect.Type
+	// fillers describes the custom functions registered to populate values of a given type.
+	fillers map[reflect.Type]FillFunc
 }
 
 // NewTypeProvider constructs a new TypeProvider instance with the provided data and default parameters.
@@ -59,21 +140,34 @@ func NewTypeProvider(data []byte) (*TypeProvider, error) {
 	// Create a new type provider from the provided data and default settings
 	t := &TypeProvider{
 		data:                 data,
+		decisionMode:         DecisionModeData,
+		exhaustionPolicy:     ExhaustionPolicyError,
+		byteOrder:            binary.BigEndian,
+		floatMode:            FloatModeRaw,
 		sliceMinSize:         0,
 		sliceMaxSize:         15,
 		sliceNilBias:         0.05,
 		mapMinSize:           0,
 		mapMaxSize:           15,
 		mapNilBias:           0.05,
+		chanMinSize:          0,
+		chanMaxSize:          15,
+		chanNilBias:          0.05,
+		funcNilBias:          0.05,
+		funcErrorBias:        0.25,
 		ptrNilBias:           0.05,
 		stringMinLength:      0,
 		stringMaxLength:      15,
 		depthLimit:           0,
 		fillUnexportedFields: true,
 		skipFieldBias:        0,
+		interestingValues:    defaultInterestingValues(),
+		dictionaryBias:       0.25,
+		mutateReplaceBias:    0.05,
+		mutateBias:           0.1,
 	}
 
-	// Call reset to create our random provider from this data.
+	// Call reset to put our provider in its initial state.
 	err := t.Reset()
 	if err != nil {
 		return nil, err
@@ -82,6 +176,57 @@ func NewTypeProvider(data []byte) (*TypeProvider, error) {
 	return t, nil
 }
 
+// GetParamsDecisionMode obtains the mode used to determine sizes, nil and skip probabilities for use with Fill and
+// other methods.
+func (t *TypeProvider) GetParamsDecisionMode() DecisionMode {
+	return t.decisionMode
+}
+
+// SetParamsDecisionMode sets the mode used to determine sizes, nil and skip probabilities for use with Fill and other
+// methods. As the decision mode changes how the data is consumed, this resets the TypeProvider.
+// Returns an error if the decision mode is invalid, or if resetting the TypeProvider failed.
+func (t *TypeProvider) SetParamsDecisionMode(mode DecisionMode) error {
+	// Validate our parameters and set them accordingly
+	if mode != DecisionModeData && mode != DecisionModeSeeded && mode != DecisionModeTail {
+		return &InvalidParamError{Param: "decision mode", Reason: mode.String()}
+	}
+	t.decisionMode = mode
+	return t.Reset()
+}
+
+// GetParamsExhaustionPolicy obtains the policy describing how reads past the end of the data are handled.
+func (t *TypeProvider) GetParamsExhaustionPolicy() ExhaustionPolicy {
+	return t.exhaustionPolicy
+}
+
+// SetParamsExhaustionPolicy sets the policy describing how reads past the end of the data are handled.
+// Returns an error if the exhaustion policy is invalid.
+func (t *TypeProvider) SetParamsExhaustionPolicy(policy ExhaustionPolicy) error {
+	// Validate our parameters and set them accordingly
+	if policy != ExhaustionPolicyError && policy != ExhaustionPolicyZero {
+		return &InvalidParamError{Param: "exhaustion policy", Reason: policy.String()}
+	}
+	t.exhaustionPolicy = policy
+	return nil
+}
+
+// GetParamsByteOrder obtains the byte order multi-byte integer and float values are read with.
+func (t *TypeProvider) GetParamsByteOrder() binary.ByteOrder {
+	return t.byteOrder
+}
+
+// SetParamsByteOrder sets the byte order multi-byte integer and float values are read with, which defaults to
+// binary.BigEndian. This applies to Fill and the getters which do not state a byte order, such as GetUint32.
+// Returns an error if the byte order is not binary.BigEndian or binary.LittleEndian.
+func (t *TypeProvider) SetParamsByteOrder(order binary.ByteOrder) error {
+	// Validate our parameters and set them accordingly
+	if order != binary.BigEndian && order != binary.LittleEndian {
+		return &InvalidParamError{Param: "byte order", Reason: fmt.Sprintf("%v", order)}
+	}
+	t.byteOrder = order
+	return nil
+}
+
 // GetParamsStringBounds obtains the minimum and maximum string length parameters for use with Fill.
 func (t *TypeProvider) GetParamsStringBounds() (int, int) {
 	return t.stringMinLength, t.stringMaxLength
@@ -92,7 +237,7 @@ func (t *TypeProvider) GetParamsStringBounds() (int, int) {
 func (t *TypeProvider) SetParamsStringBounds(minSize int, maxSize int) error {
 	// Validate our parameters and set them accordingly
 	if minSize < 0 || maxSize < minSize {
-		return fmt.Errorf("invalid string length bounds provided: min: %d, max: %d", minSize, maxSize)
+		return &InvalidParamError{Param: "string length bounds", Reason: fmt.Sprintf("min: %d, max: %d", minSize, maxSize)}
 	}
 	t.stringMinLength = minSize
 	t.stringMaxLength = maxSize
@@ -109,7 +254,7 @@ func (t *TypeProvider) GetParamsMapBounds() (int, int) {
 func (t *TypeProvider) SetParamsMapBounds(minSize int, maxSize int) error {
 	// Validate our parameters and set them accordingly
 	if minSize < 0 || maxSize < minSize {
-		return fmt.Errorf("invalid map bounds provided: min: %d, max: %d", minSize, maxSize)
+		return &InvalidParamError{Param: "map bounds", Reason: fmt.Sprintf("min: %d, max: %d", minSize, maxSize)}
 	}
 	t.mapMinSize = minSize
 	t.mapMaxSize = maxSize
@@ -126,7 +271,7 @@ func (t *TypeProvider) GetParamsSliceBounds() (int, int) {
 func (t *TypeProvider) SetParamsSliceBounds(minSize int, maxSize int) error {
 	// Validate our parameters and set them accordingly
 	if minSize < 0 || maxSize < minSize {
-		return fmt.Errorf("invalid slice bounds provided: min: %d, max: %d", minSize, maxSize)
+		return &InvalidParamError{Param: "slice bounds", Reason: fmt.Sprintf("min: %d, max: %d", minSize, maxSize)}
 	}
 	t.sliceMinSize = minSize
 	t.sliceMaxSize = maxSize
@@ -147,7 +292,7 @@ func (t *TypeProvider) SetParamsBiases(mapNilBias float32, ptrNilBias float32, s
 	// Validate our parameters
 	if mapNilBias < 0 || mapNilBias > 1 || ptrNilBias < 0 || ptrNilBias > 1 ||
 		sliceNilBias < 0 || sliceNilBias > 1 || skipFieldBias < 0 || skipFieldBias > 1 {
-		return errors.New("invalid bias provided. bias must be between [0,1]")
+		return &InvalidParamError{Param: "bias", Reason: "bias must be between [0,1]"}
 	}
 
 	// Set our nil biases
@@ -161,10 +306,17 @@ func (t *TypeProvider) SetParamsBiases(mapNilBias float32, ptrNilBias float32, s
 }
 
 // SetParamsBiasesCommon sets bias parameters for this TypeProvider, indicating the probability of nil fills or fields
-// being skipped. This differs from SetParamsBiases as it sets all nil biases from a single common value.
+// being skipped. This differs from SetParamsBiases as it sets all nil biases from a single common value, including the
+// nil biases for channels and func-typed values.
 // Returns an error if any bias value was not within the [0,1] range.
 func (t *TypeProvider) SetParamsBiasesCommon(nilBias float32, skipFieldBias float32) error {
-	return t.SetParamsBiases(nilBias, nilBias, nilBias, skipFieldBias)
+	err := t.SetParamsBiases(nilBias, nilBias, nilBias, skipFieldBias)
+	if err != nil {
+		return err
+	}
+	t.chanNilBias = nilBias
+	t.funcNilBias = nilBias
+	return nil
 }
 
 // GetParamsFillUnexportedFields gets a parameter indicating whether unexported struct fields should be filled when
@@ -179,18 +331,34 @@ func (t *TypeProvider) SetParamsFillUnexportedFields(fill bool) {
 	t.fillUnexportedFields = fill
 }
 
+// GetParamsVarintIntegers gets a parameter indicating whether multi-byte integer values are read as varints when
+// using Fill.
+func (t *TypeProvider) GetParamsVarintIntegers() bool {
+	return t.varintIntegers
+}
+
+// SetParamsVarintIntegers sets a parameter indicating that multi-byte integer values should be read as varints (see
+// GetVarint and GetUvarint) rather than fixed-width values when using Fill. Small values then consume fewer bytes,
+// keeping inputs compact. Fields with a range constraint are unaffected.
+func (t *TypeProvider) SetParamsVarintIntegers(varint bool) {
+	t.varintIntegers = varint
+}
+
 // GetParamsDepthLimit gets the depth limit parameter used when filling nested structures recursively using Fill.
 func (t *TypeProvider) GetParamsDepthLimit() int {
 	return t.depthLimit
 }
 
 // SetParamsDepthLimit sets the depth limit when filling nested structures recursively using Fill. Setting this value to zero
-// triggers a special case indicating infinite depth.
+// triggers a special case indicating infinite depth. Structs and recursive container types which contain themselves
+// without an intermediate struct, such as `type Tree map[string]Tree`, count as a level of depth, while other
+// containers only do so if SetParamsDepthCountContainers is enabled. Values which cannot be filled within the limit
+// are set to their zero value.
 // Returns an error if the depth limit is negative.
 func (t *TypeProvider) SetParamsDepthLimit(depthLimit int) error {
 	// Validate our parameters and set them accordingly
 	if depthLimit < 0 {
-		return fmt.Errorf("invalid depth limit provided: %d. depth limit cannot be negative", depthLimit)
+		return &InvalidParamError{Param: "depth limit", Reason: fmt.Sprintf("%d: depth limit cannot be negative", depthLimit)}
 	}
 	t.depthLimit = depthLimit
 	return nil
@@ -201,18 +369,14 @@ func (t *TypeProvider) SetParamsDepthLimit(depthLimit int) error {
 func (t *TypeProvider) validateBounds(expectedCount int) error {
 	// If our expected count of bytes to read is negative, return an error as the caller likely had an arithmetic issue.
 	if expectedCount < 0 {
-		return fmt.Errorf("attempted to read a negative amount of bytes: %d", expectedCount)
-	}
-
-	// If our position is out of bounds, return an error.
-	if t.position < 0 || len(t.data) < t.position {
-		return fmt.Errorf("position out of bounds: (position: %d / length: %d)", t.position, len(t.data))
+		return &InvalidParamError{Param: "read length", Reason: fmt.Sprintf("attempted to read a negative amount of bytes: %d", expectedCount)}
 	}
 
-	// If there aren't enough bytes left, return an error.
-	bytesLeft := len(t.data) - t.position
-	if bytesLeft < expectedCount {
-		return fmt.Errorf("end of stream reached: could not read %d bytes (position: %d / length: %d)", expectedCount, t.position, len(t.data))
+	// If our position is out of bounds, or there aren't enough bytes left between our position and end, return an
+	// error.
+	bytesLeft := t.end - t.position
+	if t.position < 0 || len(t.data) < t.end || bytesLeft < expectedCount {
+		return &EndOfStreamError{Requested: expectedCount, Position: t.position, End: t.end, Length: len(t.data)}
 	}
 
 	// Return no error
@@ -221,25 +385,42 @@ func (t *TypeProvider) validateBounds(expectedCount int) error {
 
 // getRandomSize obtains a random int in the positive int range.
 func (t *TypeProvider) getRandomSize(min int, max int) int {
+	// If we're making decisions from our data, obtain our size from it.
+	if t.decisionMode != DecisionModeSeeded {
+		return int(t.getDecisionUint(uint64(max - min))) + min
+	}
+
 	// Obtain a random size.
 	return t.randomProvider.Intn((max - min) + 1)  + min
 }
 
 // getRandomBool obtains a random boolean given a probability between 0 and 1.
 func (t *TypeProvider) getRandomBool(probability float32) bool {
+	// If we're making decisions from our data, obtain our boolean from it.
+	if t.decisionMode != DecisionModeSeeded {
+		return t.getDecisionBool(probability)
+	}
 	return t.randomProvider.Float32() < probability
 }
 
-// Reset resets the position to extract data from in the stream and reconstructs the random provider with the seed
-// read from the first few bytes. This puts the TypeProvider in the same state as when it was created, unless the
-// underlying TypeProviderConfig was changed.
+// Reset resets the positions to extract data from in the stream. When using DecisionModeSeeded, this also
+// reconstructs the random provider with the seed read from the first few bytes. This puts the TypeProvider in the same
+// state as when it was created, unless the underlying TypeProviderConfig was changed.
 func (t *TypeProvider) Reset() error {
-	// Set the position to zero.
+	// Set the position to zero and the end to the end of our data, and clear any recorded function calls.
 	t.position = 0
+	t.end = len(t.data)
+	t.exhausted = false
 	t.randomProvider = nil
+	t.funcCalls = nil
+
+	// If we're making decisions from our data, we don't need a random provider.
+	if t.decisionMode != DecisionModeSeeded {
+		return nil
+	}
 
-	// Read our random seed from the first int64
-	seed, err := t.GetInt64()
+	// Read our random seed from the first int64. This is always big-endian, so the byte order does not change our seed.
+	seed, err := t.GetInt64BE()
 	if err != nil {
 		return err
 	}
@@ -253,6 +434,11 @@ func (t *TypeProvider) Reset() error {
 // This advances the position the provided length.
 // Returns the requested bytes, or an error if the end of stream has been reached.
 func (t *TypeProvider) GetNBytes(length int) ([]byte, error) {
+	// If we're exhausted, return an empty slice.
+	if t.exhaust(length) {
+		return []byte{}, nil
+	}
+
 	// Validate our boundaries
 	err := t.validateBounds(length)
 	if err != nil {
@@ -269,6 +455,11 @@ func (t *TypeProvider) GetNBytes(length int) ([]byte, error) {
 // This advances the position by 1.
 // Returns the single read byte, or an error if the end of stream has been reached.
 func (t *TypeProvider) GetByte() (byte, error) {
+	// If we're exhausted, return a zero value.
+	if t.exhaust(1) {
+		return 0, nil
+	}
+
 	// Validate our boundaries
 	err := t.validateBounds(1)
 	if err != nil {
@@ -294,6 +485,11 @@ func (t *TypeProvider) GetBool() (bool, error) {
 // This advances the position by 1.
 // Returns the read uint8, or an error if the end of stream has been reached.
 func (t *TypeProvider) GetUint8() (uint8, error) {
+	// Determine if we should produce an interesting value instead of reading one.
+	if x, ok := t.getInterestingValue(reflect.Uint8); ok {
+		return uint8(x), nil
+	}
+
 	// Obtain a byte and return it as the requested type.
 	b, err := t.GetByte()
 	return uint8(b), err
@@ -303,6 +499,11 @@ func (t *TypeProvider) GetUint8() (uint8, error) {
 // This advances the position by 1.
 // Returns the read int8, or an error if the end of stream has been reached.
 func (t *TypeProvider) GetInt8() (int8, error) {
+	// Determine if we should produce an interesting value instead of reading one.
+	if x, ok := t.getInterestingValue(reflect.Int8); ok {
+		return int8(x), nil
+	}
+
 	// Obtain a byte and return it as the requested type.
 	b, err := t.GetByte()
 	return int8(b), err
@@ -312,22 +513,26 @@ func (t *TypeProvider) GetInt8() (int8, error) {
 // This advances the position by 2.
 // Returns the read uint16, or an error if the end of stream has been reached.
 func (t *TypeProvider) GetUint16() (uint16, error) {
-	// Obtain the data to back our value
-	b, err := t.GetNBytes(2)
-	if err != nil {
-		return 0, err
+	// Determine if we should produce an interesting value instead of reading one.
+	if x, ok := t.getInterestingValue(reflect.Uint16); ok {
+		return uint16(x), nil
 	}
 
-	// Convert our data to an uint16 and return
-	return binary.BigEndian.Uint16(b), nil
+	// Obtain our value using our byte order.
+	return t.readUint16()
 }
 
 // GetInt16 obtains an int16 from the current position in the buffer.
 // This advances the position by 2.
 // Returns the read int16, or an error if the end of stream has been reached.
 func (t *TypeProvider) GetInt16() (int16, error) {
+	// Determine if we should produce an interesting value instead of reading one.
+	if x, ok := t.getInterestingValue(reflect.Int16); ok {
+		return int16(x), nil
+	}
+
 	// Obtain an uint16 and convert it to an int16
-	x, err := t.GetUint16()
+	x, err := t.readUint16()
 	return int16(x), err
 }
 
@@ -335,22 +540,26 @@ func (t *TypeProvider) GetInt16() (int16, error) {
 // This advances the position by 4.
 // Returns the read uint32, or an error if the end of stream has been reached.
 func (t *TypeProvider) GetUint32() (uint32, error) {
-	// Obtain the data to back our value
-	b, err := t.GetNBytes(4)
-	if err != nil {
-		return 0, err
+	// Determine if we should produce an interesting value instead of reading one.
+	if x, ok := t.getInterestingValue(reflect.Uint32); ok {
+		return uint32(x), nil
 	}
 
-	// Convert our data to an uint32 and return
-	return binary.BigEndian.Uint32(b), nil
+	// Obtain our value using our byte order.
+	return t.readUint32()
 }
 
 // GetInt32 obtains an int32 from the current position in the buffer.
 // This advances the position by 4.
 // Returns the read int32, or an error if the end of stream has been reached.
 func (t *TypeProvider) GetInt32() (int32, error) {
+	// Determine if we should produce an interesting value instead of reading one.
+	if x, ok := t.getInterestingValue(reflect.Int32); ok {
+		return int32(x), nil
+	}
+
 	// Obtain an uint32 and convert it to an int32
-	x, err := t.GetUint32()
+	x, err := t.readUint32()
 	return int32(x), err
 }
 
@@ -358,22 +567,26 @@ func (t *TypeProvider) GetInt32() (int32, error) {
 // This advances the position by 8.
 // Returns the read uint64, or an error if the end of stream has been reached.
 func (t *TypeProvider) GetUint64() (uint64, error) {
-	// Obtain the data to back our value
-	b, err := t.GetNBytes(8)
-	if err != nil {
-		return 0, err
+	// Determine if we should produce an interesting value instead of reading one.
+	if x, ok := t.getInterestingValue(reflect.Uint64); ok {
+		return x, nil
 	}
 
-	// Convert our data to an uint64 and return
-	return binary.BigEndian.Uint64(b), nil
+	// Obtain our value using our byte order.
+	return t.readUint64()
 }
 
 // GetInt64 obtains an int64 from the current position in the buffer.
 // This advances the position by 64.
 // Returns the read int64, or an error if the end of stream has been reached.
 func (t *TypeProvider) GetInt64() (int64, error) {
+	// Determine if we should produce an interesting value instead of reading one.
+	if x, ok := t.getInterestingValue(reflect.Int64); ok {
+		return int64(x), nil
+	}
+
 	// Obtain an uint64 and convert it to an int64
-	x, err := t.GetUint64()
+	x, err := t.readUint64()
 	return int64(x), err
 }
 
@@ -381,8 +594,13 @@ func (t *TypeProvider) GetInt64() (int64, error) {
 // This advances the position by 8, reading an uint64 and casting it to the architecture-dependent width.
 // Returns the read uint, or an error if the end of stream has been reached.
 func (t *TypeProvider) GetUint() (uint, error) {
+	// Determine if we should produce an interesting value instead of reading one.
+	if x, ok := t.getInterestingValue(reflect.Uint); ok {
+		return uint(x), nil
+	}
+
 	// Obtain an uint64 and convert it to an uint
-	x, err := t.GetUint64()
+	x, err := t.readUint64()
 	return uint(x), err
 }
 
@@ -390,8 +608,13 @@ func (t *TypeProvider) GetUint() (uint, error) {
 // This advances the position by 8, reading an int64 and casting it to the architecture-dependent width.
 // Returns the read int, or an error if the end of stream has been reached.
 func (t *TypeProvider) GetInt() (int, error) {
+	// Determine if we should produce an interesting value instead of reading one.
+	if x, ok := t.getInterestingValue(reflect.Int); ok {
+		return int(x), nil
+	}
+
 	// Obtain an uint64 and convert it to an int
-	x, err := t.GetUint64()
+	x, err := t.readUint64()
 	return int(x), err
 }
 
@@ -399,8 +622,13 @@ func (t *TypeProvider) GetInt() (int, error) {
 // This advances the position by 4.
 // Returns the read float32, or an error if the end of stream has been reached.
 func (t *TypeProvider) GetFloat32() (float32, error) {
+	// Determine if we should produce an interesting value instead of reading one.
+	if x, ok := t.getInterestingValue(reflect.Float32); ok {
+		return math.Float32frombits(uint32(x)), nil
+	}
+
 	// Obtain an uint32 and convert it to a float32
-	x, err := t.GetUint32()
+	x, err := t.readUint32()
 	return math.Float32frombits(x), err
 }
 
@@ -408,29 +636,35 @@ func (t *TypeProvider) GetFloat32() (float32, error) {
 // This advances the position by 8.
 // Returns the read float64, or an error if the end of stream has been reached.
 func (t *TypeProvider) GetFloat64() (float64, error) {
+	// Determine if we should produce an interesting value instead of reading one.
+	if x, ok := t.getInterestingValue(reflect.Float64); ok {
+		return math.Float64frombits(x), nil
+	}
+
 	// Obtain an uint64 and convert it to a float64
-	x, err := t.GetUint64()
+	x, err := t.readUint64()
 	return math.Float64frombits(x), err
 }
 
-// GetFixedString obtains a string of the requested length from the current position in the buffer.
-// This advances the position the provided length.
+// GetFixedString obtains a string of the requested length from the current position in the buffer, according to the
+// string mode parameter. The length counts bytes, or runes if the string count runes parameter is set.
+// This advances the position by the amount of data the string was produced from.
 // Returns a string of the requested length, or an error if the end of stream has been reached.
 func (t *TypeProvider) GetFixedString(length int) (string, error) {
-	// Obtain bytes to convert to a string.
-	b, err := t.GetNBytes(length)
-	if err != nil {
-		return "", err
-	}
-
-	// Return a string from the bytes
-	return string(b), nil
+	// Obtain a string according to our string mode.
+	return t.readString(length)
 }
 
-// GetBytes obtains a number of bytes of length within the range settings provided in the TypeProvider.
-// This advances the position by len(result)
+// GetBytes obtains a number of bytes of length within the range settings provided in the TypeProvider. If a dictionary
+// is set, the bytes may instead be a dictionary token, or have one spliced into them.
+// This advances the position by the amount of data the bytes were produced from.
 // Returns the read bytes, or an error if the end of stream has been reached.
 func (t *TypeProvider) GetBytes() ([]byte, error) {
+	// If our dictionary is used, return the bytes produced from it.
+	if b, ok, err := t.getDictionaryValue(t.sliceMinSize, t.sliceMaxSize, false); ok || err != nil {
+		return b, err
+	}
+
 	// Obtain a random size to read
 	x := t.getRandomSize(t.sliceMinSize, t.sliceMaxSize)
 
@@ -438,20 +672,29 @@ func (t *TypeProvider) GetBytes() ([]byte, error) {
 	return t.GetNBytes(x)
 }
 
-// GetString obtains a string of length within the range settings provided in the TypeProvider.
-// This advances the position by len(result)
+// GetString obtains a string of length within the range settings provided in the TypeProvider, according to the
+// string mode parameter. If a dictionary is set, the string may instead be a dictionary token, or have one spliced
+// into it.
+// This advances the position by the amount of data the string was produced from.
 // Returns the read string, or an error if the end of stream has been reached.
 func (t *TypeProvider) GetString() (string, error) {
-	// Obtain a random size to read
-	x := t.getRandomSize(t.stringMinLength, t.stringMaxLength)
+	return t.getString(t.stringMinLength, t.stringMaxLength)
+}
 
-	// Use the random to determine how many bytes to read, then obtain them and return.
-	b, err := t.GetNBytes(x)
-	if err != nil {
-		return "", err
+// getString obtains a string of length within the provided range, according to the string mode parameter.
+// This advances the position by the amount of data the string was produced from.
+// Returns the read string, or an error if the end of stream has been reached.
+func (t *TypeProvider) getString(minLength int, maxLength int) (string, error) {
+	// If our dictionary is used, return the string produced from it.
+	if b, ok, err := t.getDictionaryValue(minLength, maxLength, true); ok || err != nil {
+		return string(b), err
 	}
 
-	return string(b), err
+	// Obtain a random size to read
+	x := t.getRandomSize(minLength, maxLength)
+
+	// Use the random size to determine the length of our string, then obtain it according to our string mode.
+	return t.readString(x)
 }
 
 // Fill populates data into a variable at a provided pointer. This can be used for structs or basic types.
@@ -460,24 +703,55 @@ func (t *TypeProvider) Fill(i interface{}) error {
 	// We should have been provided a pointer, so we obtain reflect pkg values and dereference.
 	v := reflect.Indirect(reflect.ValueOf(i))
 
-	// Next we fill the value.
-	return t.fillValue(v, 0)
+	// Next we fill the value, with a new set of values which may be reused.
+	return t.withAliases(func() error {
+		return t.fillValue(v, 0, nil)
+	})
 }
 
 // fillValue populates data into a variable based on reflection. Given the provided parameters, structures and simple
-// types can be recursively populated. See documentation surrounding the Fill method for more details.
+// types can be recursively populated. See documentation surrounding the Fill method for more details. If the value is
+// a struct field with a `fuzz` tag, the provided constraints override the TypeProvider's parameters for it, otherwise
+// they are nil.
 // Returns an error if one is encountered.
-func (t *TypeProvider) fillValue(v reflect.Value, currentDepth int) error {
+func (t *TypeProvider) fillValue(v reflect.Value, currentDepth int, constraints *fieldConstraints) error {
 	// If we can't set the value, we can stop immediately.
 	if !v.CanSet() {
 		return nil
 	}
 
 	// Determine if we should skip this field
-	if t.getRandomBool(t.skipFieldBias) {
+	if t.getRandomBool(constraints.getSkipBias(t.skipFieldBias)) {
 		return nil
 	}
 
+	// If a custom fill method exists for this type, use it rather than populating the value based on its kind.
+	if filled, err := t.fillCustom(v); filled {
+		return err
+	}
+
+	// If this value has an integer range constraint, read it directly within its range.
+	if constraints.hasIntegerRange(v) {
+		return t.fillIntegerRange(v, constraints)
+	}
+
+	// If this value should be read as a varint, do so rather than populating it based on its kind.
+	if filled, err := t.fillVarint(v); filled {
+		return err
+	}
+
+	// If this value can't be filled within our depth limit, it may only reuse a value created earlier. Otherwise set it
+	// to its zero value without making any further decisions.
+	if t.depthExceeded(v.Type(), currentDepth) {
+		if !t.fillAlias(v, constraints) {
+			v.Set(reflect.Zero(v.Type()))
+		}
+		return nil
+	}
+
+	// Fields and elements of our value are walked in the same way Encode and Mutate walk them, filling each of them.
+	w := &walker{t: t, visit: t.fillValue}
+
 	// Determine how to set our value based on its type.
 	if v.Kind() == reflect.Bool {
 		bl, err := t.GetBool()
@@ -546,51 +820,58 @@ func (t *TypeProvider) fillValue(v reflect.Value, currentDepth int) error {
 		}
 		v.SetUint(uint64(u))
 	} else if v.Kind() == reflect.Float32 {
-		f32, err := t.GetFloat32()
+		f32, err := t.getFillFloat32()
 		if err != nil {
 			return err
 		}
 		v.SetFloat(float64(f32))
 	} else if v.Kind() == reflect.Float64 {
-		f64, err := t.GetFloat64()
+		f64, err := t.getFillFloat64()
 		if err != nil {
 			return err
 		}
 		v.SetFloat(f64)
 	} else if v.Kind() == reflect.Complex64 {
-		f, err := t.GetFloat32()
+		f, err := t.getFillFloat32()
 		if err != nil {
 			return err
 		}
-		f2, err := t.GetFloat32()
+		f2, err := t.getFillFloat32()
 		if err != nil {
 			return err
 		}
 		v.SetComplex(complex128(complex(f, f2)))
 	} else if v.Kind() == reflect.Complex128 {
-		f, err := t.GetFloat64()
+		f, err := t.getFillFloat64()
 		if err != nil {
 			return err
 		}
-		f2, err := t.GetFloat64()
+		f2, err := t.getFillFloat64()
 		if err != nil {
 			return err
 		}
 		v.SetComplex(complex(f, f2))
 	}else if v.Kind() == reflect.String {
-		s, err := t.GetString()
+		// Strings with a pattern constraint are generated from it, otherwise they're read within our length bounds.
+		var s string
+		var err error
+		if pattern := constraints.getPattern(); pattern != nil {
+			s, err = t.getStringMatching(pattern)
+		} else {
+			s, err = t.getString(constraints.getLengthBounds(t.stringMinLength, t.stringMaxLength))
+		}
 		if err != nil {
 			return err
 		}
 		v.SetString(s)
 	} else if v.Kind() == reflect.Slice {
-		// Determine if the slice will be nil or if we'll actually populate it.
-		if t.getRandomBool(t.sliceNilBias) {
+		// Determine if the slice will be nil, reuse a slice created earlier, or if we'll actually populate it.
+		if t.getRandomBool(constraints.getNilBias(t.sliceNilBias)) {
 			// Set nil slice
 			v.Set(reflect.Zero(v.Type()))
-		} else {
+		} else if !t.fillAlias(v, constraints) {
 			// Obtain a random size
-			sliceSize := t.getRandomSize(t.sliceMinSize, t.sliceMaxSize)
+			sliceSize := t.getRandomSize(constraints.getLengthBounds(t.sliceMinSize, t.sliceMaxSize))
 
 			// Typically, we just create a slice here and loop for each element and fill it. But we add a special case here
 			// for byte arrays, as they're very common. Setting each element individually will take too long, so we read
@@ -602,30 +883,31 @@ func (t *TypeProvider) fillValue(v reflect.Value, currentDepth int) error {
 					return err
 				}
 				v.SetBytes(b)
+				t.addAlias(v)
 			} else {
-				// If this isn't a byte array, create a generic slice of the correct type and fill it.
-				slice := reflect.MakeSlice(v.Type(), sliceSize, sliceSize)
-				for i := 0; i < sliceSize; i++ {
-					err := t.fillValue(slice.Index(i), currentDepth)
-					if err != nil {
-						return err
-					}
+				// If this isn't a byte array, create a generic slice of the correct type, set it so it may be reused
+				// by its own elements, and fill it.
+				v.Set(reflect.MakeSlice(v.Type(), sliceSize, sliceSize))
+				t.addAlias(v)
+				err := w.walkElements(v, currentDepth)
+				if err != nil {
+					return err
 				}
-				// Set our slice value
-				v.Set(slice)
 			}
 		}
 	} else if v.Kind() == reflect.Map {
-		// Determine if the map will be nil or if we'll actually populate it.
-		if t.getRandomBool(t.mapNilBias) {
+		// Determine if the map will be nil, reuse a map created earlier, or if we'll actually populate it.
+		if t.getRandomBool(constraints.getNilBias(t.mapNilBias)) {
 			// Set nil map
 			v.Set(reflect.Zero(v.Type()))
-		} else {
+		} else if !t.fillAlias(v, constraints) {
 			// Obtain a random size
-			mapSize := t.getRandomSize(t.mapMinSize, t.mapMaxSize)
+			mapSize := t.getRandomSize(constraints.getLengthBounds(t.mapMinSize, t.mapMaxSize))
 
-			// Create our map and set it now, so we can proceed to create key-value pairs for it.
+			// Create our map and set it now, so we can proceed to create key-value pairs for it, and so it may be reused
+			// by its own keys and values.
 			v.Set(reflect.MakeMap(v.Type()))
+			t.addAlias(v)
 
 			// Loop for each element we wish to create
 			for i := 0; i < mapSize; i++ {
@@ -634,63 +916,106 @@ func (t *TypeProvider) fillValue(v reflect.Value, currentDepth int) error {
 				mValue := reflect.New(v.Type().Elem()).Elem()
 
 				// Populate the key and value
-				err := t.fillValue(mKey, currentDepth)
+				err := t.fillValue(mKey, t.elemDepth(v.Type(), currentDepth), nil)
 				if err != nil {
-					return err
+					return wrapFillError(err, fmt.Sprintf("[key %d]", i))
 				}
-				err = t.fillValue(mValue, currentDepth)
+				err = t.fillValue(mValue, t.elemDepth(v.Type(), currentDepth), nil)
 				if err != nil {
-					return err
+					return wrapFillError(err, fmt.Sprintf("[value %d]", i))
 				}
 
 				// Set the key-value pair in our dictionary
 				v.SetMapIndex(mKey, mValue)
 			}
 		}
+	} else if v.Kind() == reflect.Chan {
+		// Determine if the channel will be nil or if we'll actually populate it.
+		if t.getRandomBool(constraints.getNilBias(t.chanNilBias)) {
+			// Set nil channel
+			v.Set(reflect.Zero(v.Type()))
+		} else {
+			// Obtain a random capacity, and the number of elements to send to our channel.
+			chanSize := t.getRandomSize(constraints.getLengthBounds(t.chanMinSize, t.chanMaxSize))
+			elemCount := t.getRandomSize(0, chanSize)
+
+			// Create a bidirectional channel, so we can send to it regardless of the direction of our type.
+			ch := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, v.Type().Elem()), chanSize)
+			for i := 0; i < elemCount; i++ {
+				elem := reflect.New(v.Type().Elem()).Elem()
+				err := t.fillValue(elem, t.elemDepth(v.Type(), currentDepth), nil)
+				if err != nil {
+					return wrapFillError(err, fmt.Sprintf("[%d]", i))
+				}
+				ch.Send(elem)
+			}
+
+			// Determine if our channel should be closed, then set it.
+			if t.getRandomBool(t.chanCloseBias) {
+				ch.Close()
+			}
+			v.Set(ch)
+		}
+	} else if v.Kind() == reflect.Func {
+		// Determine if the function will be nil or if we'll synthesize one which fills its results when called.
+		if t.getRandomBool(constraints.getNilBias(t.funcNilBias)) {
+			// Set nil function
+			v.Set(reflect.Zero(v.Type()))
+		} else {
+			v.Set(t.makeFunc(v.Type(), currentDepth))
+		}
 	} else if v.Kind() == reflect.Ptr {
-		// Determine if the pointer will be nil or if we'll actually populate assign it to a populated value.
-		if t.getRandomBool(t.ptrNilBias) {
+		// Determine if the pointer will be nil, reuse a pointer created earlier, or if we'll actually populate assign it
+		// to a populated value.
+		if t.getRandomBool(constraints.getNilBias(t.ptrNilBias)) {
 			// Set nil ptr
 			v.Set(reflect.Zero(v.Type()))
-		} else {
-			// If it's a pointer, we need to create a new underlying type to live at the pointer, then populate it.
+		} else if !t.fillAlias(v, constraints) {
+			// If it's a pointer, we need to create a new underlying type to live at the pointer, then populate it. The
+			// pointer may be reused as soon as it's created, so the value it points to may refer back to it.
 			v.Set(reflect.New(v.Type().Elem()))
-			err := t.fillValue(v.Elem(), currentDepth)
+			t.addAlias(v)
+			err := w.walkPointer(v, currentDepth, constraints)
 			if err != nil {
 				return err
 			}
 		}
+	} else if v.Kind() == reflect.Interface {
+		// Interfaces can only be populated if concrete types were registered for them, otherwise they are skipped.
+		implTypes := t.implementations[v.Type()]
+		if len(implTypes) > 0 {
+			// Determine if the interface will be nil or if we'll actually assign it a populated value.
+			if t.getRandomBool(constraints.getNilBias(t.ptrNilBias)) {
+				// Set nil interface
+				v.Set(reflect.Zero(v.Type()))
+			} else {
+				// Choose one of our concrete types, create a value of it, populate it, and assign it to our interface.
+				implType := implTypes[t.getRandomSize(0, len(implTypes) - 1)]
+				impl := reflect.New(implType).Elem()
+				err := t.fillValue(impl, t.elemDepth(v.Type(), currentDepth), constraints.elemConstraints())
+				if err != nil {
+					return err
+				}
+				v.Set(impl)
+			}
+		}
 	} else if v.Kind() == reflect.Array {
 		// Loop through each element and fill it recursively.
-		for i := 0; i < v.Len(); i++ {
-			err := t.fillValue(v.Index(i), currentDepth)
-			if err != nil {
-				return err
-			}
+		err := w.walkElements(v, currentDepth)
+		if err != nil {
+			return err
 		}
-	} else if v.Kind() == reflect.Struct && (t.depthLimit == 0 || t.depthLimit > currentDepth) {
+	} else if v.Kind() == reflect.Struct {
 		// For structs we need to recursively populate every field
-		for i := 0; i < v.NumField(); i++ {
-			field := v.Field(i)
-
-			// If it's private and we're not setting private fields, skip it
-			if !field.CanSet() {
-				if !t.fillUnexportedFields {
-					continue
-				}
-				// If we are filling private fields, we continue by creating a new one here.
-				// Reference: https://stackoverflow.com/questions/42664837/how-to-access-unexported-struct-fields
-				field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
-			}
-
-			// Now we're ready to set our data, so fill it accordingly.
-			err := t.fillValue(field, currentDepth + 1)
-			if err != nil {
-				return err
-			}
+		err := w.walkStruct(v, currentDepth)
+		if err != nil {
+			return err
 		}
 	}
 
+	// If this value has a float range constraint, map our filled value into it.
+	constraints.constrainValue(v)
+
 	// Unknown value types are simply skipped/ignored, so we continue to fuzz what we're able to.
 	return nil
 }
@@ -25,9 +25,10 @@ func TestSimpleTypes(t *testing.T) {
 	// Create our fuzz data
 	b := generateTestData(256)
 
-	// Create our type provider
+	// Create our type provider, using seeded decisions which read a random seed from the first 8 bytes.
 	tp, err := go_fuzz_utils.NewTypeProvider(b)
 	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsDecisionMode(go_fuzz_utils.DecisionModeSeeded))
 
 	// Basic values
 	b1, err := tp.GetByte()
@@ -100,12 +101,111 @@ func TestSimpleTypes(t *testing.T) {
 	assert.EqualValues(t, 7, len(strFixed))
 }
 
+func TestDataDecisions(t *testing.T) {
+	// Create our type provider with data where each decision is explicitly provided.
+	b := []byte{
+		3, 'a', 'b', 'c', // a slice size byte followed by its data
+		127,              // a nil decision byte below 0.5 (scaled to 128)
+		128, 1, 0,        // a nil decision byte above 0.5, a size byte and its data
+		0x01, 0x2C,       // a two byte size for a range wider than a byte (300)
+	}
+	tp, err := go_fuzz_utils.NewTypeProvider(b)
+	assert.Nil(t, err)
+	assert.EqualValues(t, go_fuzz_utils.DecisionModeData, tp.GetParamsDecisionMode())
+
+	// Obtain dynamic bytes, which should consume a single size byte.
+	bytesDynamic, err := tp.GetBytes()
+	assert.Nil(t, err)
+	assert.EqualValues(t, []byte("abc"), bytesDynamic)
+
+	// Fill slices with a nil bias, the first being nil and the second being populated.
+	assert.Nil(t, tp.SetParamsBiasesCommon(0.5, 0))
+	assert.Nil(t, tp.SetParamsSliceBounds(0, 2))
+	var nilSlice, slice []bool
+	assert.Nil(t, tp.Fill(&nilSlice))
+	assert.Nil(t, nilSlice)
+	assert.Nil(t, tp.Fill(&slice))
+	assert.EqualValues(t, []bool{true}, slice)
+
+	// Obtain a size from a wider range, which should consume two bytes.
+	assert.Nil(t, tp.SetParamsSliceBounds(0, 1000))
+	assert.Nil(t, tp.SetParamsBiasesCommon(0, 0))
+	var wideSlice []struct{}
+	assert.Nil(t, tp.Fill(&wideSlice))
+	assert.EqualValues(t, 300, len(wideSlice))
+
+	// At the end of our data, decisions should produce minimum sizes and non-nil values.
+	assert.Nil(t, tp.SetParamsSliceBounds(2, 15))
+	assert.Nil(t, tp.SetParamsBiasesCommon(0.5, 0))
+	var emptySlice []struct{}
+	assert.Nil(t, tp.Fill(&emptySlice))
+	assert.EqualValues(t, 2, len(emptySlice))
+}
+
+func TestTailDecisions(t *testing.T) {
+	// Create our type provider with values at the start of the data, and decisions at the end.
+	b := []byte{
+		'a', 'b', 'c', 'd', 'e', // values read from the start
+		0x2C, 0x01, // a two byte size for a range wider than a byte (300), consumed from the end
+		2, 0, // a nil decision byte below 0.5 (scaled to 128) followed by a size byte, consumed from the end
+		3, // a size byte, consumed from the end
+	}
+	tp, err := go_fuzz_utils.NewTypeProvider(b)
+	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsDecisionMode(go_fuzz_utils.DecisionModeTail))
+
+	// Obtain dynamic bytes, which should consume their size from the end.
+	bytesDynamic, err := tp.GetBytes()
+	assert.Nil(t, err)
+	assert.EqualValues(t, []byte("abc"), bytesDynamic)
+
+	// Fill a nil slice followed by a populated one.
+	assert.Nil(t, tp.SetParamsBiasesCommon(0.5, 0))
+	var nilSlice []byte
+	assert.Nil(t, tp.Fill(&nilSlice))
+	assert.Nil(t, nilSlice)
+	assert.Nil(t, tp.SetParamsBiasesCommon(0, 0))
+	var slice []byte
+	assert.Nil(t, tp.Fill(&slice))
+	assert.EqualValues(t, []byte("de"), slice)
+
+	// Obtain a size from a wider range, which should consume two bytes from the end.
+	assert.Nil(t, tp.SetParamsSliceBounds(0, 1000))
+	var wideSlice []struct{}
+	assert.Nil(t, tp.Fill(&wideSlice))
+	assert.EqualValues(t, 300, len(wideSlice))
+
+	// Our data should now be fully consumed from both ends.
+	_, err = tp.GetByte()
+	assert.NotNil(t, err)
+
+	// Reset our provider and mutate our values, which should not change any decisions.
+	b[0], b[1], b[4] = 'x', 'y', 'z'
+	assert.Nil(t, tp.Reset())
+	assert.Nil(t, tp.SetParamsSliceBounds(0, 15))
+	bytesDynamic, err = tp.GetBytes()
+	assert.Nil(t, err)
+	assert.EqualValues(t, []byte("xyc"), bytesDynamic)
+
+	// Values should not be able to consume data which was already consumed by decisions.
+	_, err = tp.GetNBytes(7)
+	assert.NotNil(t, err)
+	b2, err := tp.GetNBytes(6)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []byte{'d', 'z', 0x2C, 0x01, 2, 0}, b2)
+}
+
 func TestPositionReachedEnd(t *testing.T) {
 	// Create our fuzz data
 	b := generateTestData(1)
 
-	// Create our type provider. We should encounter an error since we need at least 64-bits to read a random seed from.
+	// Create our type provider. Decisions are made from the data by default, so no seed is needed.
 	tp, err := go_fuzz_utils.NewTypeProvider(b)
+	assert.Nil(t, err)
+
+	// Switch to seeded decisions. We should encounter an error since we need at least 64-bits to read a random seed
+	// from.
+	err = tp.SetParamsDecisionMode(go_fuzz_utils.DecisionModeSeeded)
 	assert.NotNil(t, err)
 
 	// Create more fuzz data
@@ -114,6 +214,7 @@ func TestPositionReachedEnd(t *testing.T) {
 	// Recreate our type provider, this time it should succeed, reading 8 bytes as a random seed, leaving 1 byte left.
 	tp, err = go_fuzz_utils.NewTypeProvider(b)
 	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsDecisionMode(go_fuzz_utils.DecisionModeSeeded))
 
 	// Assert the values are as expected
 	b1, err := tp.GetByte()
@@ -180,6 +281,107 @@ func TestPositionReachedEnd(t *testing.T) {
 	b, err = tp.GetNBytes(0)
 	assert.Nil(t, err)
 	assert.EqualValues(t, 0, len(b))
+
+	// Errors should not be recorded as exhaustion.
+	assert.EqualValues(t, go_fuzz_utils.ExhaustionPolicyError, tp.GetParamsExhaustionPolicy())
+	assert.False(t, tp.Exhausted())
+
+	// Reset our provider and switch to producing zero values past the end of the data.
+	assert.Nil(t, tp.Reset())
+	assert.Nil(t, tp.SetParamsExhaustionPolicy(go_fuzz_utils.ExhaustionPolicyZero))
+	assert.NotNil(t, tp.SetParamsExhaustionPolicy(go_fuzz_utils.ExhaustionPolicy(-1)))
+	b1, err = tp.GetByte()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0xF7, b1)
+	assert.False(t, tp.Exhausted())
+
+	// Now expect zero values reading any type.
+	bt, err := tp.GetByte()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, bt)
+	assert.True(t, tp.Exhausted())
+
+	bl, err := tp.GetBool()
+	assert.Nil(t, err)
+	assert.EqualValues(t, true, bl) // a zero byte is even
+
+	i8, err := tp.GetInt8()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, i8)
+
+	u8, err := tp.GetUint8()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, u8)
+
+	i16, err := tp.GetInt16()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, i16)
+
+	u16, err := tp.GetUint16()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, u16)
+
+	i32, err := tp.GetInt32()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, i32)
+
+	u32, err := tp.GetUint32()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, u32)
+
+	i64, err := tp.GetInt64()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, i64)
+
+	u64, err := tp.GetUint64()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, u64)
+
+	i, err := tp.GetInt()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, i)
+
+	u, err := tp.GetUint()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, u)
+
+	f32, err := tp.GetFloat32()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, f32)
+
+	f64, err := tp.GetFloat64()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, f64)
+
+	s, err = tp.GetString()
+	assert.Nil(t, err)
+	assert.EqualValues(t, "", s)
+
+	b, err = tp.GetBytes()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(b))
+
+	s, err = tp.GetFixedString(1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "", s)
+
+	b, err = tp.GetNBytes(1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(b))
+
+	// Negative lengths should still produce errors.
+	_, err = tp.GetNBytes(-1)
+	assert.NotNil(t, err)
+
+	// Filling a structure should succeed, producing zero values.
+	st := testStruct{}
+	assert.Nil(t, tp.Fill(&st))
+	assert.EqualValues(t, "", st.PublicString)
+	assert.EqualValues(t, 0, st.PublicByte)
+
+	// Resetting our provider should clear our exhaustion.
+	assert.Nil(t, tp.Reset())
+	assert.False(t, tp.Exhausted())
 }
 
 type testStruct struct {
@@ -268,9 +470,10 @@ func TestFillBasicTypes(t *testing.T) {
 	// Create our fuzz data
 	b := generateTestData(0x1000)
 
-	// Create our type provider
+	// Create our type provider, using seeded decisions which read a random seed from the first 8 bytes.
 	tp, err := go_fuzz_utils.NewTypeProvider(b)
 	assert.Nil(t, err)
+	assert.Nil(t, tp.SetParamsDecisionMode(go_fuzz_utils.DecisionModeSeeded))
 
 	// Create a test structure and fill it.
 	st := testStruct{}
@@ -0,0 +1,118 @@
+package go_fuzz_utils
+
+import (
+	"encoding/binary"
+	"reflect"
+)
+
+// GetUvarint obtains an uint64 encoded as an unsigned varint (LEB128), in the format used by encoding/binary, from the
+// current position in the buffer. Small values therefore consume fewer bytes, with values below 128 consuming one.
+// Varints longer than binary.MaxVarintLen64 are cut off at that length, discarding any bits which overflow 64 bits.
+// This advances the position by the length of the varint.
+// Returns the read uint64, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetUvarint() (uint64, error) {
+	// Determine if we should produce an interesting value instead of reading one.
+	if x, ok := t.getInterestingValue(reflect.Uint64); ok {
+		return x, nil
+	}
+	return t.readUvarint()
+}
+
+// readUvarint obtains an uint64 encoded as an unsigned varint from the current position in the buffer. See GetUvarint
+// for more details.
+// Returns the read uint64, or an error if the end of stream has been reached.
+func (t *TypeProvider) readUvarint() (uint64, error) {
+	// Determine the length of our varint, which ends at the first byte without its continuation bit set. If the data
+	// ends before this, we request one byte more than remains, so the end of stream is handled by getFixedBytes.
+	length := 1
+	for i := t.position; i < t.end && t.data[i] >= 0x80 && length < binary.MaxVarintLen64; i++ {
+		length++
+	}
+
+	// Obtain the data to back our value
+	b, err := t.getFixedBytes(length)
+	if err != nil {
+		return 0, err
+	}
+
+	// Construct our value from the low 7 bits of each byte, least significant group first.
+	x := uint64(0)
+	for i, byteValue := range b {
+		x |= uint64(byteValue&0x7F) << (7 * i)
+	}
+	return x, nil
+}
+
+// GetVarint obtains an int64 encoded as a zig-zag signed varint, in the format used by encoding/binary, from the
+// current position in the buffer. Values with a small magnitude therefore consume fewer bytes, with values in the
+// range [-64, 63] consuming one.
+// This advances the position by the length of the varint.
+// Returns the read int64, or an error if the end of stream has been reached.
+func (t *TypeProvider) GetVarint() (int64, error) {
+	// Determine if we should produce an interesting value instead of reading one.
+	if x, ok := t.getInterestingValue(reflect.Int64); ok {
+		return int64(x), nil
+	}
+	return t.readVarint()
+}
+
+// readVarint obtains an int64 encoded as a zig-zag signed varint from the current position in the buffer. See
+// GetVarint for more details.
+// Returns the read int64, or an error if the end of stream has been reached.
+func (t *TypeProvider) readVarint() (int64, error) {
+	// Obtain our unsigned value and undo its zig-zag encoding.
+	ux, err := t.readUvarint()
+	x := int64(ux >> 1)
+	if ux&1 != 0 {
+		x = ^x
+	}
+	return x, err
+}
+
+// isVarintKind indicates whether values of the provided kind are read as varints when the varint integers parameter
+// is enabled. Single-byte integers are never read as varints, as they could not become any smaller.
+func isVarintKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// fillVarint populates an integer value from a varint, if the varint integers parameter is enabled. Values which do
+// not fit the integer's width are truncated.
+// Returns a boolean indicating whether the value was populated, and an error if one was encountered.
+func (t *TypeProvider) fillVarint(v reflect.Value) (bool, error) {
+	// If we aren't reading varints, or this kind is not read as one, the value should be populated based on its kind.
+	if !t.varintIntegers || !isVarintKind(v.Kind()) {
+		return false, nil
+	}
+
+	// Determine if we should produce an interesting value for this kind instead of reading one.
+	if x, ok := t.getInterestingValue(v.Kind()); ok {
+		if v.CanInt() {
+			v.SetInt(int64(x))
+		} else {
+			v.SetUint(x)
+		}
+		return true, nil
+	}
+
+	// Read our value as a signed or unsigned varint depending on its kind.
+	if v.CanInt() {
+		x, err := t.readVarint()
+		if err != nil {
+			return true, err
+		}
+		v.SetInt(x)
+	} else {
+		x, err := t.readUvarint()
+		if err != nil {
+			return true, err
+		}
+		v.SetUint(x)
+	}
+	return true, nil
+}
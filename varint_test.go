@@ -0,0 +1,94 @@
+package go_fuzz_utils_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/go-fuzz-utils"
+)
+
+func TestVarints(t *testing.T) {
+	// Create our data from a number of varints encoded by encoding/binary.
+	var b []byte
+	buf := make([]byte, binary.MaxVarintLen64)
+	uvarints := []uint64{0, 1, 127, 128, 300, math.MaxUint64}
+	for _, x := range uvarints {
+		b = append(b, buf[:binary.PutUvarint(buf, x)]...)
+	}
+	varints := []int64{0, -1, 63, -64, 64, math.MinInt64, math.MaxInt64}
+	for _, x := range varints {
+		b = append(b, buf[:binary.PutVarint(buf, x)]...)
+	}
+
+	// Create our type provider
+	tp, err := go_fuzz_utils.NewTypeProvider(b)
+	assert.Nil(t, err)
+
+	// Verify we read back each value.
+	for _, expected := range uvarints {
+		x, err := tp.GetUvarint()
+		assert.Nil(t, err)
+		assert.EqualValues(t, expected, x)
+	}
+	for _, expected := range varints {
+		x, err := tp.GetVarint()
+		assert.Nil(t, err)
+		assert.EqualValues(t, expected, x)
+	}
+
+	// Reads past the end of our data return an error.
+	_, err = tp.GetUvarint()
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrEndOfStream))
+
+	// Truncated varints return an error without consuming any data.
+	tp, err = go_fuzz_utils.NewTypeProvider([]byte{0x80, 0x80})
+	assert.Nil(t, err)
+	_, err = tp.GetVarint()
+	assert.True(t, errors.Is(err, go_fuzz_utils.ErrEndOfStream))
+	bt, err := tp.GetByte()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x80, bt)
+
+	// Varints longer than the maximum length are cut off at it.
+	tp, err = go_fuzz_utils.NewTypeProvider([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x05})
+	assert.Nil(t, err)
+	u64, err := tp.GetUvarint()
+	assert.Nil(t, err)
+	assert.EqualValues(t, uint64(math.MaxUint64), u64)
+	bt, err = tp.GetByte()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x05, bt)
+}
+
+type varintStruct struct {
+	I   int
+	I8  int8
+	I16 int16
+	U   uint
+	U8  uint8
+	U64 uint64
+	Age uint32 `fuzz:"min=18,max=99"`
+}
+
+func TestFillVarints(t *testing.T) {
+	// Create our type provider with varint integers enabled.
+	tp, err := go_fuzz_utils.NewTypeProvider([]byte{0x03, 0xFF, 0x80, 0x02, 0x05, 0x06, 0x01, 0x00, 0x00})
+	assert.Nil(t, err)
+	assert.False(t, tp.GetParamsVarintIntegers())
+	tp.SetParamsVarintIntegers(true)
+	assert.True(t, tp.GetParamsVarintIntegers())
+
+	// Fill our structure and verify small values consumed a single byte, while single-byte integers and fields with
+	// range constraints were read as usual.
+	var v varintStruct
+	assert.Nil(t, tp.Fill(&v))
+	assert.EqualValues(t, varintStruct{I: -2, I8: -1, I16: 128, U: 5, U8: 6, U64: 1, Age: 18}, v)
+
+	// Verify values encode to the same data they're filled from.
+	data, err := tp.Encode(&v)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []byte{0x03, 0xFF, 0x80, 0x02, 0x05, 0x06, 0x01, 0x00, 0x00}, data)
+}
@@ -0,0 +1,272 @@
+package go_fuzz_utils
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"unsafe"
+)
+
+// walkFunc describes a function which visits a value at the provided depth. If the value is a struct field with a
+// `fuzz` tag, the provided constraints override the TypeProvider's parameters for it, otherwise they are nil.
+// Returns an error if one is encountered.
+type walkFunc func(v reflect.Value, currentDepth int, constraints *fieldConstraints) error
+
+// walker walks the fields of structs and the elements of containers in the same way for Fill, Encode and Mutate, so
+// they agree on which values are visited, at which depth and with which constraints. Each of them provides its own
+// callbacks to visit values with.
+type walker struct {
+	// t represents the TypeProvider whose parameters determine which values are walked.
+	t *TypeProvider
+	// visit represents the function each field or element walked is visited with.
+	visit walkFunc
+	// skip represents a function called for each struct field which Fill does not populate, with the reason why. If
+	// it is nil, such fields are not visited.
+	skip func(v reflect.Value, reason string) error
+	// mapKeys indicates whether map keys are visited, rather than only map values.
+	mapKeys bool
+	// setCopies indicates whether map values and values held by interfaces, which are visited as copies as they
+	// can't be set directly, are set back once visited.
+	setCopies bool
+}
+
+// walkStruct visits every field of a struct which Fill populates, with the constraints parsed from its `fuzz` tag.
+// Unexported fields are visited if the TypeProvider fills them.
+// Returns an error if a field tag is invalid, or if visiting a field returns one.
+func (w *walker) walkStruct(v reflect.Value, currentDepth int) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+
+		// Parse any constraints provided for this field through its struct tag.
+		var tagConstraints *fieldConstraints
+		structField := v.Type().Field(i)
+		if tag, ok := structField.Tag.Lookup(fieldTagName); ok {
+			var err error
+			tagConstraints, err = parseFieldTag(tag, structField.Type)
+			if err != nil {
+				return newInvalidTagError(structField, v.Type(), err)
+			}
+
+			// If this field should never be filled, skip it.
+			if tagConstraints.never {
+				if err := w.skipField(field, structField, "its field is never filled"); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		// If it's private and we're not setting private fields, skip it.
+		if !field.CanSet() {
+			if !w.t.fillUnexportedFields {
+				if err := w.skipField(field, structField, "unexported fields are not filled"); err != nil {
+					return err
+				}
+				continue
+			}
+			// If we are filling private fields, we continue by creating a new one here.
+			// Reference: https://stackoverflow.com/questions/42664837/how-to-access-unexported-struct-fields
+			field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+		}
+
+		// Now we're ready to visit our field.
+		err := w.visit(field, currentDepth+1, tagConstraints)
+		if err != nil {
+			return wrapFillError(err, "."+structField.Name)
+		}
+	}
+	return nil
+}
+
+// skipField calls the skip callback for a struct field which Fill does not populate, if there is one.
+// Returns an error if the skip callback returns one.
+func (w *walker) skipField(field reflect.Value, structField reflect.StructField, reason string) error {
+	if w.skip == nil {
+		return nil
+	}
+	if err := w.skip(field, reason); err != nil {
+		return wrapFillError(err, "."+structField.Name)
+	}
+	return nil
+}
+
+// walkElements visits every element of an array or slice.
+// Returns an error if visiting an element returns one.
+func (w *walker) walkElements(v reflect.Value, currentDepth int) error {
+	for i := 0; i < v.Len(); i++ {
+		err := w.visit(v.Index(i), w.t.elemDepth(v.Type(), currentDepth), nil)
+		if err != nil {
+			return wrapFillError(err, fmt.Sprintf("[%d]", i))
+		}
+	}
+	return nil
+}
+
+// walkMap visits every key-value pair of a map in the order of their keys, as done by sortedMapEntries, so decisions
+// made for them are reproducible. Keys and values are visited as addressable copies. Values whose keys can't be looked
+// up, such as NaN, can't be set back, so they're only visited if copies are not set back.
+// Returns an error if visiting a key or value returns one.
+func (w *walker) walkMap(v reflect.Value, currentDepth int) error {
+	for i, entry := range sortedMapEntries(v) {
+		// Visit a copy of our key, if we visit keys.
+		if w.mapKeys {
+			key := reflect.New(v.Type().Key()).Elem()
+			key.Set(entry.key)
+			err := w.visit(key, w.t.elemDepth(v.Type(), currentDepth), nil)
+			if err != nil {
+				return wrapFillError(err, fmt.Sprintf("[key %d]", i))
+			}
+		}
+
+		// Visit a copy of our value, and set it back in our map if we should.
+		if w.setCopies && !entry.settable {
+			continue
+		}
+		value := reflect.New(v.Type().Elem()).Elem()
+		value.Set(entry.value)
+		err := w.visit(value, w.t.elemDepth(v.Type(), currentDepth), nil)
+		if err != nil {
+			return wrapFillError(err, fmt.Sprintf("[value %d]", i))
+		}
+		if w.setCopies {
+			v.SetMapIndex(entry.key, value)
+		}
+	}
+	return nil
+}
+
+// walkPointer visits the value a non-nil pointer points to, which inherits the constraints of the pointer.
+// Returns an error if visiting the value returns one.
+func (w *walker) walkPointer(v reflect.Value, currentDepth int, constraints *fieldConstraints) error {
+	return w.visit(v.Elem(), w.t.elemDepth(v.Type(), currentDepth), constraints.elemConstraints())
+}
+
+// walkInterface visits the value held by a non-nil interface as an addressable copy, which inherits the constraints
+// of the interface, and sets it back in the interface if copies are set back.
+// Returns an error if visiting the value returns one.
+func (w *walker) walkInterface(v reflect.Value, currentDepth int, constraints *fieldConstraints) error {
+	impl := reflect.New(v.Elem().Type()).Elem()
+	impl.Set(v.Elem())
+	err := w.visit(impl, w.t.elemDepth(v.Type(), currentDepth), constraints.elemConstraints())
+	if err != nil {
+		return err
+	}
+	if w.setCopies {
+		v.Set(impl)
+	}
+	return nil
+}
+
+// mapEntry describes a key-value pair in a map.
+type mapEntry struct {
+	// key represents the key of the pair.
+	key reflect.Value
+	// value represents the value of the pair.
+	value reflect.Value
+	// settable indicates whether the key can be looked up, so its value can be set. Keys which are not equal to
+	// themselves, such as NaN, cannot be.
+	settable bool
+}
+
+// sortedMapEntries obtains the key-value pairs of a map, ordered by their keys as done by compareValues, so decisions
+// made for them are reproducible. Keys which compare equal, such as pointers and NaN, keep the order of iteration.
+// Returns the sorted key-value pairs.
+func sortedMapEntries(v reflect.Value) []mapEntry {
+	// Collect our key-value pairs together, as keys such as NaN can't be looked up again.
+	entries := make([]mapEntry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		entries = append(entries, mapEntry{key: iter.Key(), value: iter.Value()})
+	}
+	for i := range entries {
+		entries[i].settable = v.MapIndex(entries[i].key).IsValid()
+	}
+
+	// Sort our key-value pairs by their keys.
+	sort.SliceStable(entries, func(i, j int) bool {
+		return compareValues(entries[i].key, entries[j].key) < 0
+	})
+	return entries
+}
+
+// compareValues compares two values of the same type by their contents, so the order does not depend on where values
+// are stored. Pointers, channels and functions are only ordered by whether they're nil, NaN is ordered before other
+// floats, and interfaces are ordered by the names of their dynamic types before their values.
+// Returns a negative number if a is ordered before b, a positive number if it is ordered after, or zero otherwise.
+func compareValues(a reflect.Value, b reflect.Value) int {
+	switch a.Kind() {
+	case reflect.Bool:
+		return compareOrdered(boolToUint(a.Bool()), boolToUint(b.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return compareOrdered(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return compareFloats(a.Float(), b.Float())
+	case reflect.Complex64, reflect.Complex128:
+		if c := compareFloats(real(a.Complex()), real(b.Complex())); c != 0 {
+			return c
+		}
+		return compareFloats(imag(a.Complex()), imag(b.Complex()))
+	case reflect.String:
+		return compareOrdered(a.String(), b.String())
+	case reflect.Array:
+		// Compare each of our elements in order.
+		for i := 0; i < a.Len(); i++ {
+			if c := compareValues(a.Index(i), b.Index(i)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case reflect.Struct:
+		// Compare each of our fields in order.
+		for i := 0; i < a.NumField(); i++ {
+			if c := compareValues(a.Field(i), b.Field(i)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case reflect.Interface:
+		// Order nil interfaces first, then by the names of our dynamic types, then by our values.
+		if a.IsNil() || b.IsNil() {
+			return compareOrdered(boolToUint(!a.IsNil()), boolToUint(!b.IsNil()))
+		} else if a.Elem().Type() != b.Elem().Type() {
+			return compareOrdered(a.Elem().Type().String(), b.Elem().Type().String())
+		}
+		return compareValues(a.Elem(), b.Elem())
+	case reflect.Ptr, reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Map, reflect.Slice:
+		// Addresses are not reproducible, so we only order nil values first.
+		return compareOrdered(boolToUint(!a.IsNil()), boolToUint(!b.IsNil()))
+	default:
+		return 0
+	}
+}
+
+// compareOrdered compares two ordered values.
+// Returns a negative number if a is less than b, a positive number if it is greater, or zero otherwise.
+func compareOrdered[T int64 | uint64 | string | float64](a T, b T) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// compareFloats compares two floats, ordering NaN before every other value.
+// Returns a negative number if a is ordered before b, a positive number if it is ordered after, or zero otherwise.
+func compareFloats(a float64, b float64) int {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return compareOrdered(boolToUint(!math.IsNaN(a)), boolToUint(!math.IsNaN(b)))
+	}
+	return compareOrdered(a, b)
+}
+
+// boolToUint converts a boolean to an integer which can be compared, where false is ordered before true.
+func boolToUint(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}